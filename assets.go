@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/raedatoui/glutils"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// embeddedAssets holds the _assets directory when the binary is built with the embedassets tag
+var embeddedAssets fs.FS
+
+// setupAssets picks where the slides load their assets from: the -assets directory, the files
+// embedded in the binary, or an _assets directory in the working directory, next to the
+// executable or in the GOPATH checkout.
+func setupAssets(dir string) error {
+	if dir != "" {
+		if !isDir(dir) {
+			return errors.New("assets directory " + dir + " doesnt exist")
+		}
+		sections.SetAssetDir(dir)
+		return nil
+	}
+	if embeddedAssets != nil {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			cache = os.TempDir()
+		}
+		sections.SetAssetFS(embeddedAssets, filepath.Join(cache, "learn-opengl-golang", "assets"))
+		return nil
+	}
+
+	candidates := []string{"_assets"}
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), "_assets"))
+	}
+	if src, err := glutils.ImportPathToDir("github.com/raedatoui/learn-opengl-golang"); err == nil {
+		candidates = append(candidates, filepath.Join(src, "_assets"))
+	}
+	for _, c := range candidates {
+		if isDir(c) {
+			sections.SetAssetDir(c)
+			return nil
+		}
+	}
+	return errors.New("cant find the _assets directory, run from the repository or use -assets")
+}
+
+func isDir(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && fi.IsDir()
+}
@@ -0,0 +1,23 @@
+//go:build embedassets
+// +build embedassets
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// The assets weigh around 45MB so they are only embedded on request:
+// go build -tags embedassets
+//
+//go:embed _assets
+var assetFiles embed.FS
+
+func init() {
+	sub, err := fs.Sub(assetFiles, "_assets")
+	if err != nil {
+		panic(err)
+	}
+	embeddedAssets = sub
+}
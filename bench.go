@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+const (
+	// benchWarmup frames run before the measured ones, the first frames pay for the
+	// shader compilation and the texture uploads
+	benchWarmup = 10
+	// benchStep is the time the animations advance by every frame, like a 60Hz display
+	benchStep = 1.0 / 60
+)
+
+// benchReport is the result of a bench run, written as JSON or CSV
+type benchReport struct {
+	Date     time.Time     `json:"date"`
+	Version  string        `json:"version"`
+	Renderer string        `json:"renderer"`
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Frames   int           `json:"frames"`
+	Slides   []benchResult `json:"slides"`
+}
+
+// benchResult holds the timings of a slide in milliseconds. The frame time is measured on
+// the CPU from Update until the GPU finished drawing, the GPU time covers Draw only.
+type benchResult struct {
+	Index     int     `json:"index"`
+	Type      string  `json:"type"`
+	Name      string  `json:"name"`
+	FrameMin  float64 `json:"frame_min_ms"`
+	FrameMean float64 `json:"frame_mean_ms"`
+	FrameP99  float64 `json:"frame_p99_ms"`
+	GPUMin    float64 `json:"gpu_min_ms"`
+	GPUMean   float64 `json:"gpu_mean_ms"`
+	GPUP99    float64 `json:"gpu_p99_ms"`
+	DrawCalls int     `json:"draw_calls"`
+	GLErrors  int     `json:"gl_errors"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// runBench runs every slide offscreen for the given number of frames and writes the report
+// to out, as CSV if its extension is .csv and as JSON otherwise.
+func runBench(frames, width, height int, out string) error {
+	// without debug, checking for errors in every phase would be timed with the slides
+	h, err := newHeadless(width, height, false)
+	if err != nil {
+		return err
+	}
+	defer h.destroy()
+
+	report := benchReport{
+		Date:     time.Now().UTC(),
+		Version:  gl.GoStr(gl.GetString(gl.VERSION)),
+		Renderer: gl.GoStr(gl.GetString(gl.RENDERER)),
+		Width:    width,
+		Height:   height,
+		Frames:   frames,
+	}
+	clock.FixedStep = benchStep
+
+	q := newGPUQueries()
+	defer q.delete()
+
+	for i, s := range slides {
+		r := benchResult{Index: i, Type: slideType(s), Name: s.GetHeader()}
+		pendingGLErrors()
+		if err := protect(func() error {
+			return h.bench(s, q, frames, &r)
+		}); err != nil {
+			r.Error = err.Error()
+		}
+		r.GLErrors = pendingGLErrors()
+		fmt.Printf("%02d %-24s frame %7.3f ms  p99 %7.3f ms  gpu %7.3f ms %s\n",
+			i, r.Type, r.FrameMean, r.FrameP99, r.GPUMean, r.Error)
+		report.Slides = append(report.Slides, r)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(out), ".csv") {
+		return report.writeCSV(out)
+	}
+	return report.writeJSON(out)
+}
+
+// pendingGLErrors counts the errors raised since the last call, GL keeps one per kind of error
+func pendingGLErrors() int {
+	n := 0
+	for n < 16 && gl.GetError() != gl.NO_ERROR {
+		n++
+	}
+	return n
+}
+
+// bench runs the slide for the warmup and the measured frames, and fills in r
+func (h *headless) bench(s sections.Slide, q *gpuQueries, frames int, r *benchResult) error {
+	clock.Set(0)
+	if err := h.open(s); err != nil {
+		return err
+	}
+	defer h.close(s)
+	// a panicking slide mustnt leave the queries running for the next one
+	active := false
+	defer func() {
+		if active {
+			q.end()
+		}
+	}()
+
+	frameTimes := make([]float64, 0, frames)
+	gpuTimes := make([]float64, 0, frames)
+	for i := 0; i < benchWarmup+frames; i++ {
+		clock.Tick()
+		sections.ResetDrawCalls()
+		start := time.Now()
+		q.begin()
+		active = true
+		h.frame(s)
+		read := q.end()
+		active = false
+		// wait for the GPU, otherwise only the time to queue the commands is measured
+		gl.Finish()
+		elapsed := time.Since(start)
+
+		// the query results lag a few frames, during the warmup they still belong to the previous slide
+		if i < benchWarmup {
+			continue
+		}
+		frameTimes = append(frameTimes, ms(elapsed))
+		if read {
+			gpuTimes = append(gpuTimes, q.GPUTime*1000)
+		}
+		r.DrawCalls = sections.ResetDrawCalls()
+	}
+	r.FrameMin, r.FrameMean, r.FrameP99 = summarize(frameTimes)
+	r.GPUMin, r.GPUMean, r.GPUP99 = summarize(gpuTimes)
+	return nil
+}
+
+// summarize returns the min, the mean and the 99th percentile of the samples
+func summarize(samples []float64) (min, mean, p99 float64) {
+	if len(samples) == 0 {
+		return 0, 0, 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	i := int(math.Ceil(0.99*float64(len(sorted)))) - 1
+	return sorted[0], sum / float64(len(sorted)), sorted[i]
+}
+
+func (r benchReport) writeJSON(name string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(b, '\n'), 0644)
+}
+
+// writeCSV writes a row per slide, the run settings are repeated on every row so the
+// files of several runs can be concatenated
+func (r benchReport) writeCSV(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{
+		"date", "renderer", "width", "height", "frames", "index", "type", "name",
+		"frame_min_ms", "frame_mean_ms", "frame_p99_ms", "gpu_min_ms", "gpu_mean_ms", "gpu_p99_ms",
+		"draw_calls", "gl_errors", "error",
+	})
+	num := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 3, 64)
+	}
+	for _, s := range r.Slides {
+		w.Write([]string{
+			r.Date.Format(time.RFC3339), r.Renderer, strconv.Itoa(r.Width), strconv.Itoa(r.Height),
+			strconv.Itoa(r.Frames), strconv.Itoa(s.Index), s.Type, s.Name,
+			num(s.FrameMin), num(s.FrameMean), num(s.FrameP99), num(s.GPUMin), num(s.GPUMean), num(s.GPUP99),
+			strconv.Itoa(s.DrawCalls), strconv.Itoa(s.GLErrors), s.Error,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
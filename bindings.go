@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// action is something the presentation does in response to a key, independently of the slide
+type action struct {
+	name string
+	// section is the section number for the jump actions
+	section int
+}
+
+func (a action) String() string {
+	if a.name == "section" {
+		return "section:" + strconv.Itoa(a.section)
+	}
+	return a.name
+}
+
+func (a action) description() string {
+	if a.name == "section" {
+		return "jump to section " + strconv.Itoa(a.section)
+	}
+	return actionNames[a.name]
+}
+
+// actionNames are the actions that can be bound, the section jump is written section:N
+var actionNames = map[string]string{
+	"next":       "next slide",
+	"previous":   "previous slide",
+	"wireframe":  "cycle the fill, line and point modes of the slide",
+	"quit":       "quit",
+	"help":       "show the key bindings",
+	"hud":        "show the frame times, GPU time and draw calls",
+	"screenshot": "save the window to a PNG",
+	"palette":    "search the slides by name",
+	"overview":   "show all the slides in a grid",
+	"record":     "start or stop recording every frame",
+	"reset":      "reset the camera and the tweaks of the slide",
+	"pause":      "pause the animations",
+	"step":       "step one frame",
+	"slower":     "slow down the animations",
+	"faster":     "speed up the animations",
+	// the camera actions go to the slides implementing sections.CameraControls
+	"camera-mode":   "cycle the first person, orbit and arcball cameras",
+	"camera-cursor": "grab or release the cursor for the camera",
+	"camera-fit":    "zoom the camera to fit the scene",
+}
+
+func parseAction(s string) (action, error) {
+	if strings.HasPrefix(s, "section:") {
+		n, err := strconv.Atoi(strings.TrimPrefix(s, "section:"))
+		if err != nil {
+			return action{}, fmt.Errorf("bad section in %q", s)
+		}
+		return action{name: "section", section: n}, nil
+	}
+	if _, ok := actionNames[s]; !ok {
+		return action{}, fmt.Errorf("unknown action %q", s)
+	}
+	return action{name: s}, nil
+}
+
+// keyNames maps the names used in the bindings file to glfw keys
+var keyNames = map[string]glfw.Key{
+	"space": glfw.KeySpace, "escape": glfw.KeyEscape, "enter": glfw.KeyEnter, "tab": glfw.KeyTab,
+	"backspace": glfw.KeyBackspace, "insert": glfw.KeyInsert, "delete": glfw.KeyDelete,
+	"right": glfw.KeyRight, "left": glfw.KeyLeft, "down": glfw.KeyDown, "up": glfw.KeyUp,
+	"pageup": glfw.KeyPageUp, "pagedown": glfw.KeyPageDown, "home": glfw.KeyHome, "end": glfw.KeyEnd,
+	"'": glfw.KeyApostrophe, ",": glfw.KeyComma, "-": glfw.KeyMinus, ".": glfw.KeyPeriod,
+	"/": glfw.KeySlash, ";": glfw.KeySemicolon, "=": glfw.KeyEqual, "[": glfw.KeyLeftBracket,
+	"\\": glfw.KeyBackslash, "]": glfw.KeyRightBracket, "`": glfw.KeyGraveAccent,
+}
+
+func init() {
+	for i := 0; i < 26; i++ {
+		keyNames[string(rune('a'+i))] = glfw.KeyA + glfw.Key(i)
+	}
+	for i := 0; i < 10; i++ {
+		keyNames[strconv.Itoa(i)] = glfw.Key0 + glfw.Key(i)
+	}
+	for i := 0; i < 12; i++ {
+		keyNames["f"+strconv.Itoa(i+1)] = glfw.KeyF1 + glfw.Key(i)
+	}
+}
+
+func keyName(k glfw.Key) string {
+	for n, key := range keyNames {
+		if key == k {
+			return n
+		}
+	}
+	return strconv.Itoa(int(k))
+}
+
+// defaultBindings uses glfw key codes, which are the same on every platform unlike scancodes
+func defaultBindings() map[glfw.Key]action {
+	b := map[glfw.Key]action{
+		glfw.KeyRight:        {name: "next"},
+		glfw.KeyPageDown:     {name: "next"},
+		glfw.KeyLeft:         {name: "previous"},
+		glfw.KeyPageUp:       {name: "previous"},
+		glfw.KeySpace:        {name: "wireframe"},
+		glfw.KeyEscape:       {name: "quit"},
+		glfw.KeyH:            {name: "help"},
+		glfw.KeyF3:           {name: "hud"},
+		glfw.KeyF12:          {name: "screenshot"},
+		glfw.KeyF9:           {name: "record"},
+		glfw.KeyTab:          {name: "overview"},
+		glfw.KeySlash:        {name: "palette"},
+		glfw.KeyBackspace:    {name: "reset"},
+		glfw.KeyP:            {name: "pause"},
+		glfw.KeyPeriod:       {name: "step"},
+		glfw.KeyLeftBracket:  {name: "slower"},
+		glfw.KeyRightBracket: {name: "faster"},
+		glfw.KeyM:            {name: "camera-mode"},
+		glfw.KeyC:            {name: "camera-cursor"},
+		glfw.KeyF:            {name: "camera-fit"},
+	}
+	for i := 0; i < 10; i++ {
+		b[glfw.Key0+glfw.Key(i)] = action{name: "section", section: i}
+	}
+	return b
+}
+
+// loadBindings reads overrides on top of the default bindings from a JSON file
+// mapping key names to actions, like {"n": "next", "q": "quit", "space": ""}.
+// An empty action removes the default binding of the key. A missing file isnt an error.
+func loadBindings(name string) (map[glfw.Key]action, error) {
+	b := defaultBindings()
+	if name == "" {
+		return b, nil
+	}
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := parseBindings(b, data); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return b, nil
+}
+
+// parseBindings applies the overrides to b in the order they are written. A key can only
+// be bound once, whatever the case of its name.
+func parseBindings(b map[glfw.Key]action, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("the bindings must be an object mapping key names to actions")
+	}
+	seen := make(map[glfw.Key]string)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		// the names of an object are always strings
+		kn := t.(string)
+		var an string
+		if err := dec.Decode(&an); err != nil {
+			return fmt.Errorf("the action of %q isnt a string", kn)
+		}
+		k, ok := keyNames[strings.ToLower(kn)]
+		if !ok {
+			return fmt.Errorf("unknown key %q", kn)
+		}
+		if prev, ok := seen[k]; ok {
+			return fmt.Errorf("%q is bound twice, also as %q", kn, prev)
+		}
+		seen[k] = kn
+		if an == "" {
+			delete(b, k)
+			continue
+		}
+		a, err := parseAction(an)
+		if err != nil {
+			return err
+		}
+		b[k] = a
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// boundKey returns the name of a key bound to the action, for the help of the slides. It
+// is empty when the action isnt bound.
+func boundKey(b map[glfw.Key]action, name string) string {
+	var names []string
+	for k, a := range b {
+		if a.name == name {
+			names = append(names, keyName(k))
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	n := names[0]
+	return strings.ToUpper(n[:1]) + n[1:]
+}
+
+// helpLines describes the active bindings, grouped by action
+func helpLines(b map[glfw.Key]action) []string {
+	byAction := make(map[action][]string)
+	for k, a := range b {
+		byAction[a] = append(byAction[a], keyName(k))
+	}
+	var lines []string
+	for a, ks := range byAction {
+		sort.Strings(ks)
+		lines = append(lines, strings.Join(ks, ", ")+": "+a.description())
+	}
+	sort.Strings(lines)
+	return lines
+}
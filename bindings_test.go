@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		in   string
+		want action
+		err  string
+	}{
+		{"next", action{name: "next"}, ""},
+		{"camera-fit", action{name: "camera-fit"}, ""},
+		{"section:3", action{name: "section", section: 3}, ""},
+		{"section:", action{}, `bad section in "section:"`},
+		{"section:x", action{}, `bad section in "section:x"`},
+		{"Next", action{}, `unknown action "Next"`},
+		{"section", action{}, `unknown action "section"`},
+	}
+	for _, tt := range tests {
+		got, err := parseAction(tt.in)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("parseAction(%q) error %v, want %q", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAction(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseBindings(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		// set are the keys bound by the file, removed the keys it unbinds
+		set     map[glfw.Key]action
+		removed []glfw.Key
+		err     string
+	}{
+		{"empty", `{}`, nil, nil, ""},
+		{"override", `{"n": "next", "F5": "section:1"}`,
+			map[glfw.Key]action{glfw.KeyN: {name: "next"}, glfw.KeyF5: {name: "section", section: 1}}, nil, ""},
+		{"rebind a default", `{"right": "previous"}`, map[glfw.Key]action{glfw.KeyRight: {name: "previous"}}, nil, ""},
+		{"unbind", `{"space": "", "m": ""}`, nil, []glfw.Key{glfw.KeySpace, glfw.KeyM}, ""},
+		{"move a camera key", `{"m": "", "v": "camera-mode"}`,
+			map[glfw.Key]action{glfw.KeyV: {name: "camera-mode"}}, []glfw.Key{glfw.KeyM}, ""},
+		{"unknown key", `{"hyper": "next"}`, nil, nil, `unknown key "hyper"`},
+		{"unknown action", `{"n": "nope"}`, nil, nil, `unknown action "nope"`},
+		{"bad section", `{"n": "section:one"}`, nil, nil, `bad section in "section:one"`},
+		{"duplicate", `{"n": "next", "n": "quit"}`, nil, nil, `"n" is bound twice, also as "n"`},
+		{"duplicate case", `{"n": "next", "N": "quit"}`, nil, nil, `"N" is bound twice, also as "n"`},
+		{"not a string", `{"n": 1}`, nil, nil, `the action of "n" isnt a string`},
+		{"not an object", `["n", "next"]`, nil, nil, "the bindings must be an object mapping key names to actions"},
+		{"not json", `n: next`, nil, nil, "the bindings must be an object mapping key names to actions"},
+		{"truncated", `{"n": "next"`, nil, nil, "unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		b := defaultBindings()
+		err := parseBindings(b, []byte(tt.json))
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		want := defaultBindings()
+		for k, a := range tt.set {
+			want[k] = a
+		}
+		for _, k := range tt.removed {
+			delete(want, k)
+		}
+		if !reflect.DeepEqual(b, want) {
+			t.Errorf("%s: got %v, want %v", tt.name, b, want)
+		}
+	}
+}
+
+func TestLoadBindings(t *testing.T) {
+	dir := t.TempDir()
+	if b, err := loadBindings(""); err != nil || !reflect.DeepEqual(b, defaultBindings()) {
+		t.Errorf("no file: %v, %v", b, err)
+	}
+	if b, err := loadBindings(filepath.Join(dir, "missing.json")); err != nil || !reflect.DeepEqual(b, defaultBindings()) {
+		t.Errorf("missing file: %v, %v", b, err)
+	}
+
+	name := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(name, []byte(`{"q": "quit", "escape": ""}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadBindings(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b[glfw.KeyQ] != (action{name: "quit"}) {
+		t.Errorf("q is bound to %v", b[glfw.KeyQ])
+	}
+	if _, ok := b[glfw.KeyEscape]; ok {
+		t.Error("escape is still bound")
+	}
+
+	if err := os.WriteFile(name, []byte(`{"hyper": "quit"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBindings(name); err == nil || !strings.HasPrefix(err.Error(), name+": ") {
+		t.Errorf("the error doesnt name the file: %v", err)
+	}
+}
+
+func TestBoundKey(t *testing.T) {
+	b := defaultBindings()
+	tests := []struct{ action, want string }{
+		{"camera-mode", "M"},
+		{"next", "Pagedown"},
+		{"hud", "F3"},
+		{"nope", ""},
+	}
+	for _, tt := range tests {
+		if got := boundKey(b, tt.action); got != tt.want {
+			t.Errorf("boundKey(%q) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultBindingsHaveNames(t *testing.T) {
+	for k, a := range defaultBindings() {
+		if _, ok := keyNames[keyName(k)]; !ok {
+			t.Errorf("the key of %s has no name", a)
+		}
+		if _, err := parseAction(a.String()); err != nil {
+			t.Errorf("default action %s: %v", a, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"log"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// slideCache keeps the slides navigated away from running but suspended, so coming back to
+// one skips InitGL and its shaders and models arent loaded again. The least recently used
+// slide is closed once more than max slides are kept or they hold more than maxMemory bytes.
+type slideCache struct {
+	max       int
+	maxMemory int
+	// slides are ordered from the least to the most recently used
+	slides []sections.Slide
+	// memory is measured when a slide is suspended, it doesnt run until it is taken out so
+	// it cant allocate more in between
+	memory map[sections.Slide]int
+
+	// close frees a slide leaving the cache and measure estimates the memory of a slide that
+	// was just suspended, the tests replace them to run without GL
+	close   func(sections.Slide)
+	measure func(sections.Slide) int
+}
+
+func newSlideCache(max, maxMemory int) *slideCache {
+	return &slideCache{
+		max:       max,
+		maxMemory: maxMemory,
+		memory:    make(map[sections.Slide]int),
+		close:     closeSlide,
+		measure:   suspendedMemory,
+	}
+}
+
+// suspendedMemory restores the default render state the slide left and estimates its memory
+func suspendedMemory(s sections.Slide) int {
+	sections.DefaultRenderState().Apply()
+	return sections.GPUMemory(s)
+}
+
+// suspend keeps the slide in the cache, or closes it when the cache is off
+func (c *slideCache) suspend(s sections.Slide) {
+	if c.max <= 0 || s == sections.Slide(errorSlide) {
+		c.close(s)
+		return
+	}
+	if err := protect(func() error {
+		beginPhase(s, "Suspend")
+		defer endPhase()
+		if sp, ok := s.(sections.Suspender); ok {
+			sp.Suspend()
+		}
+		return nil
+	}); err != nil {
+		log.Printf("slide %s failed suspending: %v", s.GetHeader(), err)
+		c.close(s)
+		return
+	}
+	c.slides = append(c.slides, s)
+	c.memory[s] = c.measure(s)
+	c.evict()
+}
+
+// take removes the slide from the cache, it returns false if it wasnt there
+func (c *slideCache) take(s sections.Slide) bool {
+	for i, cached := range c.slides {
+		if cached == s {
+			c.slides = append(c.slides[:i], c.slides[i+1:]...)
+			delete(c.memory, s)
+			return true
+		}
+	}
+	return false
+}
+
+// resume brings a suspended slide back, with the viewport it missed. It returns false if the
+// slide isnt in the cache and needs InitGL.
+func (c *slideCache) resume(s sections.Slide) (bool, error) {
+	if !c.take(s) {
+		return false, nil
+	}
+	return true, protect(func() error {
+		beginPhase(s, "Resume")
+		defer endPhase()
+		sections.Resize(s, viewport)
+		if sp, ok := s.(sections.Suspender); ok {
+			sp.Resume()
+		}
+		return nil
+	})
+}
+
+// evict closes the least recently used slides until the cache is within its limits
+func (c *slideCache) evict() {
+	for len(c.slides) > 0 && (len(c.slides) > c.max || c.total() > c.maxMemory) {
+		s := c.slides[0]
+		c.take(s)
+		c.close(s)
+	}
+}
+
+func (c *slideCache) total() int {
+	total := 0
+	for _, m := range c.memory {
+		total += m
+	}
+	return total
+}
+
+// clear closes all the suspended slides
+func (c *slideCache) clear() {
+	for len(c.slides) > 0 {
+		s := c.slides[0]
+		c.take(s)
+		c.close(s)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// cachedSlide counts the calls the cache makes, it can panic when suspended
+type cachedSlide struct {
+	sections.BaseSketch
+	suspends, resumes int
+	panics            bool
+}
+
+func (s *cachedSlide) Suspend() {
+	if s.panics {
+		panic("suspend")
+	}
+	s.suspends++
+}
+
+func (s *cachedSlide) Resume() {
+	s.resumes++
+}
+
+// testCache is a cache that records the slides it closes and reads their memory from sizes
+type testCache struct {
+	*slideCache
+	closed []sections.Slide
+	sizes  map[sections.Slide]int
+}
+
+func newTestCache(max, maxMemory int) *testCache {
+	c := &testCache{slideCache: newSlideCache(max, maxMemory), sizes: make(map[sections.Slide]int)}
+	c.close = func(s sections.Slide) { c.closed = append(c.closed, s) }
+	c.measure = func(s sections.Slide) int { return c.sizes[s] }
+	return c
+}
+
+func newCachedSlides(names ...string) []*cachedSlide {
+	var s []*cachedSlide
+	for _, n := range names {
+		c := &cachedSlide{}
+		c.SetName(n)
+		s = append(s, c)
+	}
+	return s
+}
+
+// names returns the names of the slides, in order
+func names(slides []sections.Slide) []string {
+	n := []string{}
+	for _, s := range slides {
+		n = append(n, s.GetName())
+	}
+	return n
+}
+
+func TestSlideCacheEviction(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		maxMemory int
+		sizes     []int
+		// the slides suspended, in order, and the ones resumed with a - prefix
+		ops    []string
+		cached []string
+		closed []string
+	}{
+		{"off", 0, 100, nil, []string{"a", "b"}, []string{}, []string{"a", "b"}},
+		{"count", 2, 100, nil, []string{"a", "b", "c"}, []string{"b", "c"}, []string{"a"}},
+		{"memory", 4, 100, []int{60, 30, 20}, []string{"a", "b", "c"}, []string{"b", "c"}, []string{"a"}},
+		{"memory evicts several", 4, 100, []int{10, 20, 90}, []string{"a", "b", "c"}, []string{"c"}, []string{"a", "b"}},
+		{"too big for the cache", 4, 100, []int{10, 150}, []string{"a", "b"}, []string{}, []string{"a", "b"}},
+		{"lru", 2, 100, nil, []string{"a", "b", "-a", "a", "c"}, []string{"a", "c"}, []string{"b"}},
+		{"resumed slides free their memory", 4, 100, []int{60, 30, 50}, []string{"a", "b", "-a", "c"}, []string{"b", "c"}, []string{}},
+	}
+	for _, tt := range tests {
+		c := newTestCache(tt.max, tt.maxMemory)
+		slides := make(map[string]*cachedSlide)
+		for i, s := range newCachedSlides("a", "b", "c") {
+			slides[s.GetName()] = s
+			if i < len(tt.sizes) {
+				c.sizes[s] = tt.sizes[i]
+			}
+		}
+		for _, op := range tt.ops {
+			if op[0] == '-' {
+				if ok, err := c.resume(slides[op[1:]]); !ok || err != nil {
+					t.Fatalf("%s: resuming %s returned %v, %v", tt.name, op[1:], ok, err)
+				}
+				continue
+			}
+			c.suspend(slides[op])
+		}
+		if got := names(c.slides); !reflect.DeepEqual(got, tt.cached) {
+			t.Errorf("%s: cached %v, want %v", tt.name, got, tt.cached)
+		}
+		if got := names(c.closed); !reflect.DeepEqual(got, tt.closed) {
+			t.Errorf("%s: closed %v, want %v", tt.name, got, tt.closed)
+		}
+		if len(c.memory) != len(c.slides) {
+			t.Errorf("%s: the memory of %d slides is kept for %d cached", tt.name, len(c.memory), len(c.slides))
+		}
+	}
+}
+
+func TestSlideCacheSuspendResume(t *testing.T) {
+	c := newTestCache(4, 100)
+	s := newCachedSlides("a", "b")
+	a, b := s[0], s[1]
+
+	c.suspend(a)
+	if a.suspends != 1 {
+		t.Errorf("suspended %d times, want 1", a.suspends)
+	}
+	if ok, err := c.resume(b); ok || err != nil {
+		t.Errorf("resuming a slide that isnt cached returned %v, %v", ok, err)
+	}
+	if b.resumes != 0 {
+		t.Error("a slide that isnt cached was resumed")
+	}
+	if ok, err := c.resume(a); !ok || err != nil {
+		t.Errorf("resuming a cached slide returned %v, %v", ok, err)
+	}
+	if a.resumes != 1 || len(c.slides) != 0 {
+		t.Errorf("resumed %d times, %d slides left", a.resumes, len(c.slides))
+	}
+
+	// a slide that panics suspending is closed, not cached
+	b.panics = true
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	c.suspend(b)
+	if len(c.slides) != 0 || len(c.closed) != 1 || c.closed[0] != b {
+		t.Errorf("cached %v, closed %v", names(c.slides), names(c.closed))
+	}
+}
+
+func TestSlideCacheTake(t *testing.T) {
+	c := newTestCache(4, 100)
+	s := newCachedSlides("a", "b", "c")
+	for _, cs := range s {
+		c.sizes[cs] = 10
+		c.suspend(cs)
+	}
+	if !c.take(s[1]) {
+		t.Fatal("b wasnt taken")
+	}
+	if c.take(s[1]) {
+		t.Error("b was taken twice")
+	}
+	if got := names(c.slides); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("cached %v, want [a c]", got)
+	}
+	if c.total() != 20 {
+		t.Errorf("total %d, want 20", c.total())
+	}
+	if len(c.closed) != 0 || s[1].resumes != 0 {
+		t.Error("take closed or resumed the slide")
+	}
+}
+
+func TestSlideCacheClear(t *testing.T) {
+	c := newTestCache(4, 100)
+	for _, cs := range newCachedSlides("a", "b", "c") {
+		c.suspend(cs)
+	}
+	c.clear()
+	if got := names(c.closed); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("closed %v, want the least recently used first", got)
+	}
+	if len(c.slides) != 0 || len(c.memory) != 0 {
+		t.Errorf("%d slides and %d sizes left", len(c.slides), len(c.memory))
+	}
+}
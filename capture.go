@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// readFrame reads the back buffer of the window, where the frame was just drawn
+func readFrame() *image.RGBA {
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, 0)
+	gl.ReadBuffer(gl.BACK)
+	return readPixels(viewport.Width, viewport.Height)
+}
+
+// saveScreenshot saves the frame in dir as a PNG named after the slide and the time
+func saveScreenshot(dir string, s sections.Slide, img image.Image) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, captureName(s)+".png")
+	return name, savePNG(name, img)
+}
+
+// captureName names the captures after the slide and the time
+func captureName(s sections.Slide) string {
+	return slideType(s) + "-" + time.Now().Format("20060102-150405.000")
+}
+
+// recorder saves every frame, as a numbered PNG sequence in a new directory under the output
+// or as a Y4M stream when the output ends with .y4m. The clock runs at a fixed step while recording
+// so the frames are evenly spaced however long they take to save.
+type recorder struct {
+	out      string
+	frames   int
+	y4m      *y4mWriter
+	lastStep float64
+}
+
+func startRecording(out string, fps int, s sections.Slide) (*recorder, error) {
+	r := &recorder{out: out, lastStep: clock.FixedStep}
+	if strings.EqualFold(filepath.Ext(out), ".y4m") {
+		if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+			return nil, err
+		}
+		w, err := newY4MWriter(out, viewport.Width, viewport.Height, fps)
+		if err != nil {
+			return nil, err
+		}
+		r.y4m = w
+	} else {
+		// every recording gets its own directory so they dont overwrite each other
+		r.out = filepath.Join(out, captureName(s))
+		if err := os.MkdirAll(r.out, 0755); err != nil {
+			return nil, err
+		}
+	}
+	clock.FixedStep = 1.0 / float64(fps)
+	return r, nil
+}
+
+func (r *recorder) add(img *image.RGBA) error {
+	r.frames++
+	if r.y4m != nil {
+		return r.y4m.write(img)
+	}
+	return savePNG(filepath.Join(r.out, fmt.Sprintf("frame-%05d.png", r.frames)), img)
+}
+
+// stop closes the stream and gives the clock back to the real time
+func (r *recorder) stop() error {
+	clock.FixedStep = r.lastStep
+	if r.y4m != nil {
+		return r.y4m.close()
+	}
+	return nil
+}
+
+// y4mWriter writes raw 4:2:0 frames in the YUV4MPEG2 format, which ffmpeg reads directly
+type y4mWriter struct {
+	f             *os.File
+	w             *bufio.Writer
+	width, height int
+	y, cb, cr     []uint8
+}
+
+func newY4MWriter(name string, width, height, fps int) (*y4mWriter, error) {
+	// the chroma planes are subsampled by 2, odd sizes lose their last row or column
+	width, height = width&^1, height&^1
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &y4mWriter{
+		f:      f,
+		w:      bufio.NewWriter(f),
+		width:  width,
+		height: height,
+		y:      make([]uint8, width*height),
+		cb:     make([]uint8, width*height/4),
+		cr:     make([]uint8, width*height/4),
+	}
+	// C420jpeg is full range, like color.RGBToYCbCr
+	if _, err := fmt.Fprintf(w.w, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C420jpeg\n", width, height, fps); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *y4mWriter) write(img *image.RGBA) error {
+	b := img.Bounds()
+	if b.Dx()&^1 != w.width || b.Dy()&^1 != w.height {
+		return fmt.Errorf("frame is %dx%d, the stream is %dx%d", b.Dx(), b.Dy(), w.width, w.height)
+	}
+	for y := 0; y < w.height; y += 2 {
+		for x := 0; x < w.width; x += 2 {
+			var cb, cr int
+			for _, p := range [4][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				c := img.RGBAAt(x+p[0], y+p[1])
+				yy, u, v := color.RGBToYCbCr(c.R, c.G, c.B)
+				w.y[(y+p[1])*w.width+x+p[0]] = yy
+				cb += int(u)
+				cr += int(v)
+			}
+			i := y/2*w.width/2 + x/2
+			w.cb[i] = uint8(cb / 4)
+			w.cr[i] = uint8(cr / 4)
+		}
+	}
+	if _, err := w.w.WriteString("FRAME\n"); err != nil {
+		return err
+	}
+	for _, plane := range [][]uint8{w.y, w.cb, w.cr} {
+		if _, err := w.w.Write(plane); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *y4mWriter) close() error {
+	if err := w.w.Flush(); err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+// capture handles the screenshot and record actions once the frame is drawn
+func capture(cfg *config) {
+	if recordPending {
+		recordPending = false
+		toggleRecording(cfg)
+	}
+	if !screenshotPending && rec == nil {
+		return
+	}
+
+	img := readFrame()
+	if screenshotPending {
+		screenshotPending = false
+		if name, err := saveScreenshot(cfg.shots, currentSlide, img); err != nil {
+			log.Printf("cant save the screenshot: %v", err)
+		} else {
+			log.Printf("saved %s", name)
+		}
+	}
+	if rec != nil {
+		if err := rec.add(img); err != nil {
+			log.Printf("recording stopped: %v", err)
+			toggleRecording(cfg)
+		}
+	}
+}
+
+func toggleRecording(cfg *config) {
+	if rec != nil {
+		if err := rec.stop(); err != nil {
+			log.Printf("cant finish the recording: %v", err)
+		}
+		log.Printf("recorded %d frames to %s", rec.frames, rec.out)
+		rec = nil
+		return
+	}
+	r, err := startRecording(cfg.record, cfg.recordFPS, currentSlide)
+	if err != nil {
+		log.Printf("cant start recording: %v", err)
+		return
+	}
+	log.Printf("recording to %s", r.out)
+	rec = r
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestY4MWriter(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	ry, rcb, rcr := color.RGBToYCbCr(red.R, red.G, red.B)
+	by, bcb, bcr := color.RGBToYCbCr(blue.R, blue.G, blue.B)
+
+	// 5x3 loses its last column and row, leaving two 2x2 blocks
+	img := solid(5, 3, red)
+	// the right block is half red and half blue
+	img.SetRGBA(2, 0, blue)
+	img.SetRGBA(3, 1, blue)
+	// outside the stream
+	img.SetRGBA(4, 0, blue)
+	img.SetRGBA(0, 2, blue)
+
+	name := filepath.Join(t.TempDir(), "out.y4m")
+	w, err := newY4MWriter(name, 5, 3, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := w.write(img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.write(solid(4, 4, red)); err == nil {
+		t.Error("wrote a frame of another size")
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := "YUV4MPEG2 W4 H2 F30:1 Ip A1:1 C420jpeg\n"
+	if !bytes.HasPrefix(data, []byte(header)) {
+		t.Fatalf("header %q, want %q", data[:bytes.IndexByte(data, '\n')+1], header)
+	}
+	frame := append([]byte("FRAME\n"),
+		ry, ry, by, ry,
+		ry, ry, ry, by,
+		rcb, uint8((int(rcb)+int(bcb))/2),
+		rcr, uint8((int(rcr)+int(bcr))/2),
+	)
+	want := append([]byte(header), frame...)
+	want = append(want, frame...)
+	if !bytes.Equal(data, want) {
+		t.Errorf("stream\n%v\nwant\n%v", data, want)
+	}
+}
+
+func TestY4MWriterSolid(t *testing.T) {
+	grey := color.RGBA{90, 120, 200, 255}
+	y, cb, cr := color.RGBToYCbCr(grey.R, grey.G, grey.B)
+	name := filepath.Join(t.TempDir(), "out.y4m")
+	w, err := newY4MWriter(name, 6, 4, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.write(solid(6, 4, grey)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.TrimPrefix(data, []byte("YUV4MPEG2 W6 H4 F24:1 Ip A1:1 C420jpeg\nFRAME\n"))
+	// a full luma plane and two quarter size chroma planes
+	for _, plane := range []struct {
+		name  string
+		size  int
+		value uint8
+	}{{"Y", 24, y}, {"Cb", 6, cb}, {"Cr", 6, cr}} {
+		if len(data) < plane.size {
+			t.Fatalf("%s plane has %d bytes, want %d", plane.name, len(data), plane.size)
+		}
+		if want := bytes.Repeat([]byte{plane.value}, plane.size); !bytes.Equal(data[:plane.size], want) {
+			t.Errorf("%s plane %v, want %v", plane.name, data[:plane.size], want)
+		}
+		data = data[plane.size:]
+	}
+	if len(data) != 0 {
+		t.Errorf("%d bytes after the frame", len(data))
+	}
+}
@@ -0,0 +1,95 @@
+//go:build linux && egl
+// +build linux,egl
+
+package main
+
+/*
+#cgo LDFLAGS: -lEGL
+#include <stdlib.h>
+#include <string.h>
+#include <EGL/egl.h>
+#include <EGL/eglext.h>
+
+#ifndef EGL_PLATFORM_SURFACELESS_MESA
+#define EGL_PLATFORM_SURFACELESS_MESA 0x31DD
+#endif
+
+static EGLDisplay getDisplay() {
+	// prefer the Mesa surfaceless platform, it doesnt need an X or wayland server
+	const char *ext = eglQueryString(EGL_NO_DISPLAY, EGL_EXTENSIONS);
+	if (ext != NULL && strstr(ext, "EGL_MESA_platform_surfaceless") != NULL) {
+		PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+			(PFNEGLGETPLATFORMDISPLAYEXTPROC) eglGetProcAddress("eglGetPlatformDisplayEXT");
+		if (getPlatformDisplay != NULL) {
+			EGLDisplay dpy = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+			if (dpy != EGL_NO_DISPLAY) {
+				return dpy;
+			}
+		}
+	}
+	return eglGetDisplay(EGL_DEFAULT_DISPLAY);
+}
+
+static int createContext(EGLDisplay dpy, int major, int minor, EGLContext *ctx) {
+	if (!eglInitialize(dpy, NULL, NULL)) {
+		return 1;
+	}
+	if (!eglBindAPI(EGL_OPENGL_API)) {
+		return 2;
+	}
+	EGLint configAttribs[] = {
+		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+		EGL_NONE
+	};
+	EGLConfig config;
+	EGLint n = 0;
+	if (!eglChooseConfig(dpy, configAttribs, &config, 1, &n) || n == 0) {
+		return 3;
+	}
+	EGLint contextAttribs[] = {
+		EGL_CONTEXT_MAJOR_VERSION, major,
+		EGL_CONTEXT_MINOR_VERSION, minor,
+		EGL_CONTEXT_OPENGL_PROFILE_MASK, EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT,
+		EGL_CONTEXT_OPENGL_FORWARD_COMPATIBLE, EGL_TRUE,
+		EGL_NONE
+	};
+	*ctx = eglCreateContext(dpy, config, EGL_NO_CONTEXT, contextAttribs);
+	if (*ctx == EGL_NO_CONTEXT) {
+		return 4;
+	}
+	// render without a surface, everything goes through our own framebuffers
+	if (!eglMakeCurrent(dpy, EGL_NO_SURFACE, EGL_NO_SURFACE, *ctx)) {
+		eglDestroyContext(dpy, *ctx);
+		return 5;
+	}
+	return 0;
+}
+*/
+import "C"
+
+import "fmt"
+
+// offscreenContext is a surfaceless EGL context. It works with software renderers like
+// Mesa llvmpipe, set EGL_PLATFORM=surfaceless or LIBGL_ALWAYS_SOFTWARE=1 to force them.
+type offscreenContext struct {
+	display C.EGLDisplay
+	context C.EGLContext
+}
+
+func newOffscreenContext(width, height int) (*offscreenContext, error) {
+	c := &offscreenContext{display: C.getDisplay()}
+	if c.display == C.EGLDisplay(C.EGL_NO_DISPLAY) {
+		return nil, fmt.Errorf("egl: no display available")
+	}
+	if code := C.createContext(c.display, 4, 1, &c.context); code != 0 {
+		C.eglTerminate(c.display)
+		return nil, fmt.Errorf("egl: failed creating a 4.1 core context, step %d error 0x%x", int(code), int(C.eglGetError()))
+	}
+	return c, nil
+}
+
+func (c *offscreenContext) destroy() {
+	C.eglMakeCurrent(c.display, C.EGLSurface(C.EGL_NO_SURFACE), C.EGLSurface(C.EGL_NO_SURFACE), C.EGLContext(C.EGL_NO_CONTEXT))
+	C.eglDestroyContext(c.display, c.context)
+	C.eglTerminate(c.display)
+}
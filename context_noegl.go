@@ -0,0 +1,15 @@
+//go:build linux && !egl
+// +build linux,!egl
+
+package main
+
+import "errors"
+
+// offscreenContext isnt available without EGL, which would make every linux build need libEGL
+type offscreenContext struct{}
+
+func newOffscreenContext(width, height int) (*offscreenContext, error) {
+	return nil, errors.New("offscreen rendering needs EGL, build with -tags egl")
+}
+
+func (c *offscreenContext) destroy() {}
@@ -0,0 +1,31 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import "github.com/go-gl/glfw/v3.2/glfw"
+
+// offscreenContext uses a hidden glfw window, since EGL isnt available on this platform.
+type offscreenContext struct {
+	window *glfw.Window
+}
+
+func newOffscreenContext(width, height int) (*offscreenContext, error) {
+	if err := glfw.Init(); err != nil {
+		return nil, err
+	}
+	windowHints()
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	w, err := glfw.CreateWindow(width, height, "learnopengl.com in Golang", nil, nil)
+	if err != nil {
+		glfw.Terminate()
+		return nil, err
+	}
+	w.MakeContextCurrent()
+	return &offscreenContext{window: w}, nil
+}
+
+func (c *offscreenContext) destroy() {
+	c.window.Destroy()
+	glfw.Terminate()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// config holds the command line options
+type config struct {
+	// window
+	width, height int
+	fullscreen    bool
+	monitor       int
+	vsync         bool
+	msaa          int
+
+	// start position
+	slide   string
+	section int
+
+	// animations
+	fixedStep float64
+
+	// watch enables the shader hot reload
+	watch bool
+
+	// assets is the directory overriding the default assets location
+	assets string
+
+	// debug checks for GL errors and logs the GL objects the slides leak
+	debug bool
+
+	// keys is the file with the key bindings overrides
+	keys string
+
+	// cache is the number of slides kept suspended, cacheMemory the megabytes they can hold
+	cache       int
+	cacheMemory int
+
+	// state is the file keeping the session between runs, resume starts on its slide
+	state  string
+	resume bool
+
+	// headless render
+	headless bool
+	out      string
+	time     float64
+
+	// bench
+	bench    int
+	benchOut string
+
+	// captures
+	shots     string
+	record    string
+	recordFPS int
+
+	// input recording
+	recordInput string
+	replay      string
+
+	// transitions
+	transition     string
+	transitionTime float64
+}
+
+func parseFlags() *config {
+	c := &config{}
+	flag.IntVar(&c.width, "width", 1280, "width of the window")
+	flag.IntVar(&c.height, "height", 1024, "height of the window")
+	flag.BoolVar(&c.fullscreen, "fullscreen", false, "open the window fullscreen on the monitor selected with -monitor")
+	flag.IntVar(&c.monitor, "monitor", 0, "index of the monitor used in fullscreen")
+	flag.BoolVar(&c.vsync, "vsync", true, "wait for the vertical sync when swapping buffers")
+	flag.IntVar(&c.msaa, "msaa", 0, "number of samples for multisample antialiasing, 0 disables it")
+
+	flag.StringVar(&c.slide, "slide", "0", "slide to start with, by index, header or type name")
+	flag.IntVar(&c.section, "section", -1, "section to start with, overrides -slide")
+
+	flag.StringVar(&c.assets, "assets", "", "directory to load the assets from instead of the embedded or default _assets")
+
+	flag.StringVar(&c.keys, "keys", configFile("keys.json"), "JSON file overriding the key bindings")
+
+	flag.IntVar(&c.cache, "cache", 4, "number of slides kept running, suspended, after leaving them so coming back is instant, 0 closes them")
+	flag.IntVar(&c.cacheMemory, "cache-memory", 256, "megabytes of buffers, textures and models the suspended slides can hold")
+
+	flag.StringVar(&c.state, "state", configFile("state.json"), "JSON file keeping the last slide, the cameras and the tweaks between runs, empty to forget them")
+
+	flag.BoolVar(&c.debug, "debug", false, "check for GL errors in every phase of the slides and log the GL objects they dont free")
+
+	flag.StringVar(&c.transition, "transition", "section", "effect between slides: section for the defaults of the sections, crossfade, slide, wipe or none")
+	flag.Float64Var(&c.transitionTime, "transition-time", 0.4, "duration of the transitions in seconds")
+
+	flag.BoolVar(&c.watch, "watch", true, "recompile the shaders of the current slide when their files change")
+
+	flag.Float64Var(&c.fixedStep, "fixed-step", 0, "advance the animations by this many seconds every frame instead of the real time")
+
+	flag.BoolVar(&c.headless, "headless", false, "render the -slide offscreen to a PNG and exit")
+	flag.StringVar(&c.out, "out", "slide.png", "output file for the headless render")
+	flag.Float64Var(&c.time, "time", 0, "seconds the animations run for before the headless render")
+
+	flag.IntVar(&c.bench, "bench", 0, "run every slide offscreen for this many frames at -width x -height and write the timings to -bench-out")
+	flag.StringVar(&c.benchOut, "bench-out", "bench.json", "report of the bench run, CSV if the name ends with .csv and JSON otherwise")
+
+	flag.StringVar(&c.shots, "shots", "screenshots", "directory for the screenshots")
+	flag.StringVar(&c.record, "record", "recordings", "directory for the recorded PNG sequences, or a .y4m file for a video stream")
+	flag.IntVar(&c.recordFPS, "record-fps", 30, "frame rate of the recordings, the animations advance by 1/fps every recorded frame")
+
+	flag.StringVar(&c.recordInput, "record-input", "", "save the key, mouse, scroll and drop events to this file")
+	flag.StringVar(&c.replay, "replay", "", "replay the events saved with -record-input, frame by frame with -fixed-step")
+	flag.Parse()
+
+	// the session picks the start slide unless one is given
+	c.resume = true
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "slide" || f.Name == "section" {
+			c.resume = false
+		}
+	})
+	return c
+}
+
+// configFile returns the path of a file in the user config directory, or an empty string
+// if the platform doesnt have one
+func configFile(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "learn-opengl-golang", name)
+}
+
+// startSlide returns the index of the slide selected with -section or -slide
+func (c *config) startSlide(slides []sections.Slide) (int, error) {
+	if c.section >= 0 {
+		cover, ok := covers[c.section]
+		if !ok {
+			return -1, fmt.Errorf("no section %d", c.section)
+		}
+		return sections.SlidePosition(slides, cover), nil
+	}
+	return findSlide(slides, c.slide)
+}
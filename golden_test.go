@@ -0,0 +1,284 @@
+package main
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+const (
+	// goldenWidth and goldenHeight are the size of the reference images
+	goldenWidth  = 640
+	goldenHeight = 512
+	// goldenTime is how long the slides run before they are captured
+	goldenTime = 1.0
+	// goldenDir holds the reference images, the diffs of the failing slides go to its failures directory
+	goldenDir = "golden"
+)
+
+var (
+	update    = flag.Bool("update", false, "rewrite the reference images instead of comparing with them")
+	tolerance = flag.Uint("tolerance", 8, "max per channel difference allowed, at most 255")
+)
+
+// TestGolden renders every slide and compares it with its reference PNG. It is skipped when
+// no GL context can be created, like on machines without EGL, and fails the slides that
+// have no reference.
+func TestGolden(t *testing.T) {
+	if *tolerance > 255 {
+		t.Fatalf("-tolerance %d is over 255", *tolerance)
+	}
+	if err := setupAssets(""); err != nil {
+		t.Fatal(err)
+	}
+	h, err := newHeadless(goldenWidth, goldenHeight, true)
+	if err != nil {
+		t.Skipf("no GL context: %v", err)
+	}
+	defer h.destroy()
+
+	for _, s := range slides {
+		s := s
+		name := goldenName(s)
+		t.Run(strings.TrimSuffix(name, ".png"), func(t *testing.T) {
+			checkGolden(t, h, s, name)
+		})
+	}
+}
+
+func checkGolden(t *testing.T, h *headless, s sections.Slide, name string) {
+	ref := filepath.Join(goldenDir, name)
+	got, err := h.render(s, goldenTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := glReport.Errors(s.GetHeader()); len(errs) > 0 {
+		for _, e := range errs {
+			t.Errorf("GL error: %s", e)
+		}
+		return
+	}
+
+	if *update {
+		if err := savePNG(ref, got); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("updated %s", ref)
+		return
+	}
+
+	want, err := loadPNG(ref)
+	if os.IsNotExist(err) {
+		t.Fatalf("no reference image, run with -update to write %s and commit it", ref)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, bad := compareImages(want, got, uint8(*tolerance))
+	if bad == 0 {
+		return
+	}
+	out := filepath.Join(goldenDir, "failures", name)
+	t.Errorf("%d pixels differ by more than %d, see %s", bad, *tolerance, out)
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := savePNG(out, diff); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// goldenName names the reference after the type of the slide, so adding a slide doesnt
+// rename the others. The covers share their type and are told apart by their name.
+func goldenName(s sections.Slide) string {
+	n := 0
+	for _, o := range slides {
+		if slideType(o) == slideType(s) {
+			n++
+		}
+	}
+	if n > 1 {
+		return slideType(s) + "-" + slug(s.GetName()) + ".png"
+	}
+	return slideType(s) + ".png"
+}
+
+// slug lower cases the name and replaces whatever isnt a letter or a digit with dashes
+func slug(name string) string {
+	f := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(f, "-")
+}
+
+// compareImages returns the number of pixels where any channel differs by more than
+// tolerance, and an image highlighting them in red over a faded copy of got.
+func compareImages(want image.Image, got *image.RGBA, tolerance uint8) (*image.RGBA, int) {
+	b := got.Bounds()
+	diff := image.NewRGBA(b)
+	if want.Bounds() != b {
+		// a size mismatch fails every pixel
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				diff.Set(x, y, color.RGBA{255, 0, 0, 255})
+			}
+		}
+		return diff, b.Dx() * b.Dy()
+	}
+
+	bad := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			w := color.RGBAModel.Convert(want.At(x, y)).(color.RGBA)
+			g := got.RGBAAt(x, y)
+			if channelDiff(w.R, g.R) > tolerance || channelDiff(w.G, g.G) > tolerance ||
+				channelDiff(w.B, g.B) > tolerance || channelDiff(w.A, g.A) > tolerance {
+				bad++
+				diff.SetRGBA(x, y, color.RGBA{255, 0, 0, 255})
+				continue
+			}
+			l := uint8((uint16(g.R) + uint16(g.G) + uint16(g.B)) / 12)
+			diff.SetRGBA(x, y, color.RGBA{l, l, l, 255})
+		}
+	}
+	return diff, bad
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func loadPNG(name string) (image.Image, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Getting Started", "getting-started"},
+		{"4c. Textures Ex3", "4c-textures-ex3"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Élan", "élan"},
+		{"---", ""},
+	}
+	for _, tt := range tests {
+		if got := slug(tt.name); got != tt.want {
+			t.Errorf("slug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+type goldenSlide struct {
+	sections.BaseSketch
+}
+
+func TestGoldenName(t *testing.T) {
+	intro := &sections.TitleSlide{}
+	intro.SetName("Intro")
+	cover := &sections.TitleSlide{}
+	cover.SetName("1. Getting Started")
+	sketch := &goldenSlide{}
+	sketch.SetName("Sketch")
+
+	defer func(s []sections.Slide) { slides = s }(slides)
+	slides = []sections.Slide{intro, sketch, cover}
+
+	tests := []struct {
+		slide sections.Slide
+		want  string
+	}{
+		{intro, "TitleSlide-intro.png"},
+		{cover, "TitleSlide-1-getting-started.png"},
+		{sketch, "goldenSlide.png"},
+	}
+	for _, tt := range tests {
+		if got := goldenName(tt.slide); got != tt.want {
+			t.Errorf("goldenName(%s) = %q, want %q", tt.slide.GetName(), got, tt.want)
+		}
+	}
+
+	// a type used once isnt suffixed
+	slides = []sections.Slide{intro, sketch}
+	if got := goldenName(intro); got != "TitleSlide.png" {
+		t.Errorf("goldenName(Intro) = %q, want TitleSlide.png", got)
+	}
+}
+
+// solid returns a w x h image filled with c
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	m := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(m.Pix); i += 4 {
+		m.Pix[i], m.Pix[i+1], m.Pix[i+2], m.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return m
+}
+
+func TestCompareImages(t *testing.T) {
+	grey := color.RGBA{100, 100, 100, 255}
+	off := solid(4, 4, grey)
+	off.SetRGBA(1, 2, color.RGBA{100, 110, 100, 255})
+	off.SetRGBA(3, 0, color.RGBA{100, 100, 100, 240})
+
+	tests := []struct {
+		name      string
+		want      image.Image
+		got       *image.RGBA
+		tolerance uint8
+		bad       int
+	}{
+		{"same", solid(4, 4, grey), solid(4, 4, grey), 0, 0},
+		{"within tolerance", solid(4, 4, grey), off, 15, 0},
+		{"over tolerance", solid(4, 4, grey), off, 8, 2},
+		{"alpha only", solid(4, 4, grey), off, 10, 1},
+		{"size mismatch", solid(4, 3, grey), solid(4, 4, grey), 255, 16},
+	}
+	for _, tt := range tests {
+		diff, bad := compareImages(tt.want, tt.got, tt.tolerance)
+		if bad != tt.bad {
+			t.Errorf("%s: %d bad pixels, want %d", tt.name, bad, tt.bad)
+		}
+		if diff.Bounds() != tt.got.Bounds() {
+			t.Errorf("%s: diff is %v, want %v", tt.name, diff.Bounds(), tt.got.Bounds())
+		}
+		red := 0
+		for i := 0; i < len(diff.Pix); i += 4 {
+			if diff.Pix[i] == 255 && diff.Pix[i+1] == 0 {
+				red++
+			}
+		}
+		if red != tt.bad {
+			t.Errorf("%s: %d red pixels in the diff, want %d", tt.name, red, tt.bad)
+		}
+	}
+}
+
+func TestChannelDiff(t *testing.T) {
+	tests := []struct{ a, b, want uint8 }{
+		{0, 0, 0},
+		{10, 3, 7},
+		{3, 10, 7},
+		{0, 255, 255},
+	}
+	for _, tt := range tests {
+		if got := channelDiff(tt.a, tt.b); got != tt.want {
+			t.Errorf("channelDiff(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
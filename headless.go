@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// headless renders slides into an offscreen framebuffer without opening a window.
+// It can run on machines without a display or a GPU.
+type headless struct {
+	ctx *offscreenContext
+	fb  *framebuffer
+}
+
+// newHeadless creates the offscreen context and the framebuffer, loads the font and initializes the slides.
+// With debug, the GL errors are collected for every phase and the leaks are logged, which
+// costs too much for timing the slides.
+func newHeadless(width, height int, debug bool) (*headless, error) {
+	viewport = sections.NewViewport(width, height, width, height)
+
+	ctx, err := newOffscreenContext(width, height)
+	if err != nil {
+		return nil, err
+	}
+	h := &headless{ctx: ctx}
+
+	if err := gl.Init(); err != nil {
+		h.destroy()
+		return nil, err
+	}
+	fmt.Println("OpenGL version", gl.GoStr(gl.GetString(gl.VERSION)), gl.GoStr(gl.GetString(gl.RENDERER)))
+	if debug {
+		glReport = sections.NewGLReport()
+		glReport.InstallDebugCallback()
+	}
+
+	if h.fb, err = newFramebuffer(width, height); err != nil {
+		h.destroy()
+		return nil, err
+	}
+	h.fb.bind()
+
+	if font, err = loadFont(); err != nil {
+		h.destroy()
+		return nil, err
+	}
+	// the clock is only moved by render, there is no real time source
+	clock = sections.NewClock(func() float64 {
+		return 0
+	})
+	if err := initSlides(font, clock, debug); err != nil {
+		h.destroy()
+		return nil, err
+	}
+	sections.DefaultRenderState().Apply()
+	return h, nil
+}
+
+// headlessStep is the time the animations advance by on every update of a headless render
+const headlessStep = 1.0 / 60
+
+// render draws the slide as it is after running for t seconds. The slide is updated every
+// headlessStep, so the slides adding up Clock.Delta get to the same state as the ones reading
+// Clock.Time. A panicking slide returns an error.
+func (h *headless) render(s sections.Slide, t float64) (img *image.RGBA, err error) {
+	step := clock.FixedStep
+	clock.FixedStep = headlessStep
+	defer func() { clock.FixedStep = step }()
+	clock.Set(0)
+
+	err = protect(func() error {
+		if err := h.open(s); err != nil {
+			return err
+		}
+		defer h.close(s)
+
+		steps := int(math.Round(t / headlessStep))
+		for i := 1; i < steps; i++ {
+			clock.Tick()
+			h.update(s)
+		}
+		if steps > 0 {
+			clock.Tick()
+		}
+		h.frame(s)
+		drawText(s)
+		gl.Finish()
+
+		img = h.fb.readPixels()
+		return nil
+	})
+	return img, err
+}
+
+// open runs InitGL and sizes the slide to the framebuffer
+func (h *headless) open(s sections.Slide) error {
+	beginPhase(s, "InitGL")
+	defer endPhase()
+	err := protect(func() error {
+		if err := s.InitGL(); err != nil {
+			return err
+		}
+		sections.Resize(s, viewport)
+		return nil
+	})
+	if err != nil {
+		s.ReleaseResources()
+	}
+	return err
+}
+
+// update runs the Update of the slide without drawing it
+func (h *headless) update(s sections.Slide) {
+	beginPhase(s, "Update")
+	defer endPhase()
+	s.Update()
+}
+
+// frame updates and draws the slide into the framebuffer, without the text
+func (h *headless) frame(s sections.Slide) {
+	h.fb.bind()
+	s.RenderState().Apply()
+	defer sections.DefaultRenderState().Apply()
+	defer endPhase()
+	beginPhase(s, "Update")
+	s.Update()
+	beginPhase(s, "Draw")
+	s.Draw(viewport)
+}
+
+// close frees the slide and restores the default state for the next one
+func (h *headless) close(s sections.Slide) {
+	beginPhase(s, "Close")
+	s.Close()
+	s.ReleaseResources()
+	endPhase()
+	sections.DefaultRenderState().Apply()
+}
+
+func (h *headless) destroy() {
+	if h.fb != nil {
+		h.fb.delete()
+	}
+	h.ctx.destroy()
+}
+
+// renderHeadless renders one frame of a slide and saves it as a PNG.
+func renderHeadless(name string, width, height int, t float64, out string) error {
+	// the headless renders are for checking the slides, so they always report errors and leaks
+	h, err := newHeadless(width, height, true)
+	if err != nil {
+		return err
+	}
+	defer h.destroy()
+
+	i, err := findSlide(slides, name)
+	if err != nil {
+		return err
+	}
+	img, err := h.render(slides[i], t)
+	if err != nil {
+		return fmt.Errorf("slide %d failed: %v", i, err)
+	}
+	if errs := glReport.Errors(slides[i].GetHeader()); len(errs) > 0 {
+		fmt.Printf("GL errors:\n%s\n", glReport)
+	}
+	return savePNG(out, img)
+}
+
+// findSlide looks up a slide by its index, its header or the name of its type
+func findSlide(slides []sections.Slide, name string) (int, error) {
+	if i, err := strconv.Atoi(name); err == nil {
+		if i < 0 || i >= len(slides) {
+			return -1, fmt.Errorf("slide index %d out of range [0, %d]", i, len(slides)-1)
+		}
+		return i, nil
+	}
+	for i, s := range slides {
+		if strings.EqualFold(s.GetHeader(), name) || strings.EqualFold(slideType(s), name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no slide named %q", name)
+}
+
+// slideType returns the name of the struct implementing the slide
+func slideType(s sections.Slide) string {
+	return reflect.TypeOf(s).Elem().Name()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/glfont"
+	"github.com/raedatoui/glutils"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// graphFrames is the number of frames shown by the frame time graph
+const graphFrames = 120
+
+// frameStats are the CPU timings of the last frame of the slide
+type frameStats struct {
+	update, draw time.Duration
+	drawCalls    int
+}
+
+// hud is the performance overlay. It shows the CPU time of Update and Draw, the GPU time
+// and the primitives of Draw, the draw calls and a graph of the last frame times.
+type hud struct {
+	gpu        *gpuQueries
+	lines      *lineRenderer
+	frameTimes [graphFrames]float64
+	next       int
+	lastFrame  time.Time
+	stats      frameStats
+}
+
+func newHUD() (*hud, error) {
+	lr, err := newLineRenderer()
+	if err != nil {
+		return nil, err
+	}
+	return &hud{gpu: newGPUQueries(), lines: lr, lastFrame: time.Now()}, nil
+}
+
+// frame records the timings of the frame that was just drawn
+func (h *hud) frame(stats frameStats) {
+	now := time.Now()
+	h.frameTimes[h.next] = now.Sub(h.lastFrame).Seconds() * 1000
+	h.next = (h.next + 1) % graphFrames
+	h.lastFrame = now
+	h.stats = stats
+}
+
+func (h *hud) render(f *glfont.Font, vp sections.Viewport) {
+	x := float32(vp.WindowWidth) - 290
+	last := h.frameTimes[(h.next+graphFrames-1)%graphFrames]
+
+	f.SetColor(1.0, 1.0, 1.0, 1.0)
+	f.Printf(x, 30, 0.25, "frame  %6.2f ms", last)
+	f.Printf(x, 48, 0.25, "update %6.2f ms cpu", ms(h.stats.update))
+	f.Printf(x, 66, 0.25, "draw   %6.2f ms cpu %6.2f ms gpu", ms(h.stats.draw), h.gpu.GPUTime*1000)
+	f.Printf(x, 84, 0.25, "%d draw calls, %d triangles", h.stats.drawCalls, h.gpu.Primitives)
+
+	// the graph goes up to 33ms, or the slowest frame
+	top := 1000.0 / 30
+	for _, t := range h.frameTimes {
+		if t > top {
+			top = t
+		}
+	}
+	gx, gy, gw, gh := x, float32(100), float32(260), float32(60)
+	y := func(t float64) float32 {
+		return gy + gh - float32(t/top)*gh
+	}
+	points := make([]float32, 0, graphFrames*2)
+	for i := 0; i < graphFrames; i++ {
+		t := h.frameTimes[(h.next+i)%graphFrames]
+		points = append(points, gx+gw*float32(i)/(graphFrames-1), y(t))
+	}
+
+	sections.OverlayRenderState().Apply()
+	h.lines.draw(gl.LINE_LOOP, []float32{gx, gy, gx + gw, gy, gx + gw, gy + gh, gx, gy + gh}, glutils.White.To32(), vp)
+	// 60 fps
+	h.lines.draw(gl.LINES, []float32{gx, y(1000.0 / 60), gx + gw, y(1000.0 / 60)}, glutils.Color32{R: 0.2, G: 0.8, B: 0.2, A: 1}, vp)
+	h.lines.draw(gl.LINE_STRIP, points, glutils.Color32{R: 1, G: 0.8, B: 0.2, A: 1}, vp)
+	sections.DefaultRenderState().Apply()
+}
+
+func (h *hud) delete() {
+	h.gpu.delete()
+	h.lines.delete()
+}
+
+func ms(d time.Duration) float64 {
+	return d.Seconds() * 1000
+}
+
+// lineRenderer draws lines given in window coordinates, like the text
+type lineRenderer struct {
+	program     uint32
+	vao, vbo    uint32
+	size, color int32
+}
+
+func newLineRenderer() (*lineRenderer, error) {
+	var vertexShader = `
+	#version 330 core
+	layout (location = 0) in vec2 position;
+	uniform vec2 size;
+	void main() {
+		gl_Position = vec4(position.x / size.x * 2.0 - 1.0, 1.0 - position.y / size.y * 2.0, 0.0, 1.0);
+	}` + "\x00"
+	var fragShader = `
+	#version 330 core
+	uniform vec4 color;
+	out vec4 outColor;
+	void main() {
+		outColor = color;
+	}` + "\x00"
+
+	program, err := glutils.BasicProgram(vertexShader, fragShader)
+	if err != nil {
+		return nil, err
+	}
+	lr := &lineRenderer{
+		program: program,
+		size:    gl.GetUniformLocation(program, gl.Str("size\x00")),
+		color:   gl.GetUniformLocation(program, gl.Str("color\x00")),
+	}
+	gl.GenVertexArrays(1, &lr.vao)
+	gl.GenBuffers(1, &lr.vbo)
+	gl.BindVertexArray(lr.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, lr.vbo)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 2*glutils.GL_FLOAT32_SIZE, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.BindVertexArray(0)
+	return lr, nil
+}
+
+func (lr *lineRenderer) draw(mode uint32, points []float32, c glutils.Color32, vp sections.Viewport) {
+	gl.UseProgram(lr.program)
+	gl.Uniform2f(lr.size, float32(vp.WindowWidth), float32(vp.WindowHeight))
+	gl.Uniform4f(lr.color, c.R, c.G, c.B, c.A)
+	gl.BindVertexArray(lr.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, lr.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(points)*glutils.GL_FLOAT32_SIZE, gl.Ptr(points), gl.STREAM_DRAW)
+	gl.DrawArrays(mode, 0, int32(len(points)/2))
+	gl.BindVertexArray(0)
+}
+
+func (lr *lineRenderer) delete() {
+	gl.DeleteVertexArrays(1, &lr.vao)
+	gl.DeleteBuffers(1, &lr.vbo)
+	gl.DeleteProgram(lr.program)
+}
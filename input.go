@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// the kinds of input events
+const (
+	keyEvent         = "key"
+	mouseEvent       = "mouse"
+	mouseButtonEvent = "button"
+	scrollEvent      = "scroll"
+	dropEvent        = "drop"
+	charEvent        = "char"
+	resizeEvent      = "resize"
+)
+
+// inputEvent is an event from one of the glfw callbacks, stamped with the frame and the time
+// since the recording started
+type inputEvent struct {
+	Frame    int              `json:"frame"`
+	Time     float64          `json:"time"`
+	Kind     string           `json:"kind"`
+	Key      glfw.Key         `json:"key,omitempty"`
+	Scancode int              `json:"scancode,omitempty"`
+	Button   glfw.MouseButton `json:"button,omitempty"`
+	Action   glfw.Action      `json:"action,omitempty"`
+	Mods     glfw.ModifierKey `json:"mods,omitempty"`
+	X        float64          `json:"x,omitempty"`
+	Y        float64          `json:"y,omitempty"`
+	Files    []string         `json:"files,omitempty"`
+	Char     rune             `json:"char,omitempty"`
+
+	// the framebuffer and the window sizes of a resize
+	Width        int `json:"width,omitempty"`
+	Height       int `json:"height,omitempty"`
+	WindowWidth  int `json:"window_width,omitempty"`
+	WindowHeight int `json:"window_height,omitempty"`
+}
+
+// dispatch sends the event where the callback would have
+func (e inputEvent) dispatch() {
+	switch e.Kind {
+	case keyEvent:
+		handleKey(e.Key, e.Scancode, e.Action, e.Mods)
+	case mouseEvent:
+		handleMouse(e.X, e.Y)
+	case mouseButtonEvent:
+		handleMouseButton(e.Button, e.Action, e.Mods)
+	case scrollEvent:
+		handleScroll(e.X, e.Y)
+	case dropEvent:
+		handleDrop(e.Files)
+	case charEvent:
+		handleChar(e.Char)
+	case resizeEvent:
+		handleResize(e.Width, e.Height, e.WindowWidth, e.WindowHeight)
+	}
+}
+
+// handleInput records the events coming from glfw and dispatches them. While replaying, only
+// the keys get through so the real mouse doesnt fight with the recorded one, and the resizes
+// so the viewport follows the window the replay resizes.
+func handleInput(e inputEvent) {
+	if player != nil && e.Kind != keyEvent && e.Kind != resizeEvent {
+		return
+	}
+	if inputRec != nil {
+		inputRec.add(e)
+	}
+	e.dispatch()
+}
+
+// inputHeader is the first line of a recording, the replay starts from the same slide
+type inputHeader struct {
+	Slide     int     `json:"slide"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	FixedStep float64 `json:"fixed_step"`
+}
+
+// inputRecorder writes the events as JSON lines, after a header line
+type inputRecorder struct {
+	f     *os.File
+	w     *bufio.Writer
+	enc   *json.Encoder
+	start float64
+	err   error
+}
+
+func newInputRecorder(name string, h inputHeader) (*inputRecorder, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	r := &inputRecorder{f: f, w: bufio.NewWriter(f), start: glfw.GetTime()}
+	r.enc = json.NewEncoder(r.w)
+	if err := r.enc.Encode(h); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *inputRecorder) add(e inputEvent) {
+	if r.err != nil {
+		return
+	}
+	e.Frame = frameCount
+	e.Time = glfw.GetTime() - r.start
+	if r.err = r.enc.Encode(e); r.err != nil {
+		log.Printf("input recording stopped: %v", r.err)
+	}
+}
+
+func (r *inputRecorder) close() error {
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	return r.err
+}
+
+// inputPlayer replays a recording. With a fixed clock step the events are replayed at the
+// frame they were recorded at, so the slide goes through exactly the same states. Otherwise
+// they follow the real time.
+type inputPlayer struct {
+	header  inputHeader
+	events  []inputEvent
+	next    int
+	byFrame bool
+	start   float64
+}
+
+func loadInputRecording(name string) (*inputPlayer, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := &inputPlayer{}
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := dec.Decode(&p.header); err != nil {
+		return nil, fmt.Errorf("bad header in %s: %v", name, err)
+	}
+	for dec.More() {
+		var e inputEvent
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("bad event %d in %s: %v", len(p.events), name, err)
+		}
+		p.events = append(p.events, e)
+	}
+	return p, nil
+}
+
+// play dispatches the events due at this frame, it returns false once they are all played
+func (p *inputPlayer) play() bool {
+	now := glfw.GetTime() - p.start
+	for ; p.next < len(p.events); p.next++ {
+		e := p.events[p.next]
+		if p.byFrame && e.Frame > frameCount || !p.byFrame && e.Time > now {
+			return true
+		}
+		e.dispatch()
+	}
+	return false
+}
+
+// startReplay goes to the recorded slide and starts playing the events from the next frame
+func startReplay(p *inputPlayer, fixedStep float64) {
+	if p.header.Width != viewport.WindowWidth || p.header.Height != viewport.WindowHeight {
+		window.SetSize(p.header.Width, p.header.Height)
+	}
+	if p.header.FixedStep != fixedStep {
+		log.Printf("the input was recorded with -fixed-step %g, replaying with %g", p.header.FixedStep, fixedStep)
+	}
+	p.byFrame = fixedStep > 0
+	p.start = glfw.GetTime()
+	gotoSlide(p.header.Slide)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// framebuffer is an offscreen render target with a color texture and a depth/stencil buffer
+type framebuffer struct {
+	fbo, texture, rbo uint32
+	width, height     int32
+}
+
+func newFramebuffer(width, height int) (*framebuffer, error) {
+	fb := &framebuffer{width: int32(width), height: int32(height)}
+
+	gl.GenFramebuffers(1, &fb.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.fbo)
+
+	// Color attachment
+	gl.GenTextures(1, &fb.texture)
+	gl.BindTexture(gl.TEXTURE_2D, fb.texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, fb.width, fb.height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, fb.texture, 0)
+
+	// Depth and stencil attachment
+	gl.GenRenderbuffers(1, &fb.rbo)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, fb.rbo)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, fb.width, fb.height)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, fb.rbo)
+
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		fb.delete()
+		return nil, errors.New("framebuffer is not complete")
+	}
+	return fb, nil
+}
+
+// bind makes the framebuffer the current render target and sets the viewport to its size
+func (fb *framebuffer) bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.fbo)
+	gl.Viewport(0, 0, fb.width, fb.height)
+}
+
+func (fb *framebuffer) unbind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// readPixels copies the color attachment into an image, flipping it so row 0 is the top
+func (fb *framebuffer) readPixels() *image.RGBA {
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fb.fbo)
+	img := readPixels(int(fb.width), int(fb.height))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, 0)
+	return img
+}
+
+func (fb *framebuffer) delete() {
+	gl.DeleteFramebuffers(1, &fb.fbo)
+	gl.DeleteTextures(1, &fb.texture)
+	gl.DeleteRenderbuffers(1, &fb.rbo)
+}
+
+// readPixels reads the currently bound read framebuffer. GL stores the bottom row first
+// so the rows are flipped to match image.RGBA.
+func readPixels(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	stride := img.Stride
+	row := make([]uint8, stride)
+	for y := 0; y < height/2; y++ {
+		top := img.Pix[y*stride : (y+1)*stride]
+		bottom := img.Pix[(height-1-y)*stride : (height-y)*stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+	return img
+}
+
+func savePNG(name string, img image.Image) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
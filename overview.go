@@ -0,0 +1,355 @@
+package main
+
+import (
+	"log"
+	"math"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/raedatoui/glutils"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// overview shows every slide running in a thumbnail, laid out in a grid. All the slides are
+// initialized while it is open, picking one keeps it running and closes the others. The
+// suspended slides are resumed and go back to the cache.
+type overview struct {
+	thumbs   []*framebuffer
+	open     []bool
+	resumed  []bool
+	previous sections.Slide
+	selected int
+
+	cols             int
+	x0, y0           float32
+	cellW, cellH     float32
+	thumbW, thumbH   float32
+	cursorX, cursorY float64
+
+	quads *quadRenderer
+	lines *lineRenderer
+}
+
+const (
+	overviewPadding = 16
+	overviewLabel   = 18
+	overviewTop     = 60
+)
+
+func openOverview() (*overview, error) {
+	o := &overview{
+		open:     make([]bool, len(slides)),
+		resumed:  make([]bool, len(slides)),
+		previous: currentSlide,
+		selected: slideIndex,
+	}
+	var err error
+	if o.quads, err = newQuadRenderer(); err != nil {
+		return nil, err
+	}
+	if o.lines, err = newLineRenderer(); err != nil {
+		o.quads.delete()
+		return nil, err
+	}
+	for i, s := range slides {
+		if s == currentSlide {
+			o.open[i] = true
+			continue
+		}
+		if ok, err := suspended.resume(s); ok {
+			if err != nil {
+				log.Printf("slide %s failed: %v", s.GetHeader(), err)
+				closeSlide(s)
+				continue
+			}
+			o.open[i], o.resumed[i] = true, true
+			continue
+		}
+		if err := protect(func() error {
+			beginPhase(s, "InitGL")
+			defer endPhase()
+			if err := s.InitGL(); err != nil {
+				return err
+			}
+			sections.Resize(s, viewport)
+			return nil
+		}); err != nil {
+			log.Printf("slide %s failed: %v", s.GetHeader(), err)
+			closeSlide(s)
+			continue
+		}
+		sess.restore(s)
+		o.open[i] = true
+	}
+	if err := o.layout(); err != nil {
+		o.close(slideIndex)
+		return nil, err
+	}
+	return o, nil
+}
+
+// layout fits the grid in the window, the thumbnails keep the aspect of the window
+func (o *overview) layout() error {
+	ww, wh := float32(viewport.WindowWidth), float32(viewport.WindowHeight)
+	n := len(slides)
+	o.cols = int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + o.cols - 1) / o.cols
+
+	o.thumbW = (ww - overviewPadding*float32(o.cols+1)) / float32(o.cols)
+	o.thumbH = o.thumbW * wh / ww
+	if h := (wh-overviewTop)/float32(rows) - overviewPadding - overviewLabel; h < o.thumbH {
+		o.thumbH = h
+		o.thumbW = h * ww / wh
+	}
+	o.cellW = o.thumbW + overviewPadding
+	o.cellH = o.thumbH + overviewPadding + overviewLabel
+	o.x0 = (ww - o.cellW*float32(o.cols) + overviewPadding) / 2
+	o.y0 = overviewTop
+
+	for _, fb := range o.thumbs {
+		if fb != nil {
+			fb.delete()
+		}
+	}
+	o.thumbs = make([]*framebuffer, n)
+	w := int(o.thumbW * float32(viewport.ScaleX))
+	h := int(o.thumbH * float32(viewport.ScaleY))
+	for i := range slides {
+		fb, err := newFramebuffer(w, h)
+		if err != nil {
+			return err
+		}
+		o.thumbs[i] = fb
+	}
+	return nil
+}
+
+// resize lays the grid out again and passes the new viewport to the slides
+func (o *overview) resize() {
+	if err := o.layout(); err != nil {
+		log.Printf("cant resize the overview: %v", err)
+	}
+	for i, s := range slides {
+		if o.open[i] {
+			sections.Resize(s, viewport)
+		}
+	}
+}
+
+// cell returns the top left corner of the thumbnail of slide i, in window coordinates
+func (o *overview) cell(i int) (float32, float32) {
+	return o.x0 + o.cellW*float32(i%o.cols), o.y0 + o.cellH*float32(i/o.cols)
+}
+
+func (o *overview) draw() {
+	for i, s := range slides {
+		if !o.open[i] {
+			continue
+		}
+		o.thumbs[i].bind()
+		renderState(s).Apply()
+		if err := protect(func() error {
+			defer endPhase()
+			beginPhase(s, "Update")
+			s.Update()
+			beginPhase(s, "Draw")
+			s.Draw(viewport)
+			return nil
+		}); err != nil {
+			log.Printf("slide %s failed: %v", s.GetHeader(), err)
+			closeSlide(s)
+			o.open[i] = false
+		}
+	}
+	sections.DefaultRenderState().Apply()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(0, 0, int32(viewport.Width), int32(viewport.Height))
+	gl.ClearColor(0.1, 0.1, 0.1, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	font.SetColor(1.0, 1.0, 1.0, 1.0)
+	font.Printf(o.x0, 35, 0.4, "Overview")
+	for i, s := range slides {
+		x, y := o.cell(i)
+		if o.open[i] {
+			o.quads.draw(o.thumbs[i].texture, x, y, o.thumbW, o.thumbH, viewport)
+		} else {
+			font.Printf(x+10, y+o.thumbH/2, 0.25, "failed, see the log")
+		}
+		font.Printf(x, y+o.thumbH+14, 0.22, firstLine(s.GetHeader()))
+	}
+
+	x, y := o.cell(o.selected)
+	x, y = x-3, y-3
+	w, h := o.thumbW+6, o.thumbH+6
+	sections.OverlayRenderState().Apply()
+	o.lines.draw(gl.LINE_LOOP, []float32{x, y, x + w, y, x + w, y + h, x, y + h}, glutils.White.To32(), viewport)
+	sections.DefaultRenderState().Apply()
+}
+
+func (o *overview) handleKey(k glfw.Key) {
+	switch k {
+	case glfw.KeyLeft:
+		o.selected--
+	case glfw.KeyRight:
+		o.selected++
+	case glfw.KeyUp:
+		o.selected -= o.cols
+	case glfw.KeyDown:
+		o.selected += o.cols
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		closeOverview(o.selected)
+		return
+	case glfw.KeyEscape:
+		closeOverview(slideIndex)
+		return
+	}
+	if o.selected < 0 {
+		o.selected = 0
+	}
+	if o.selected >= len(slides) {
+		o.selected = len(slides) - 1
+	}
+}
+
+// at returns the slide under the cursor, or -1
+func (o *overview) at(xpos, ypos float64) int {
+	x, y := float32(xpos)-o.x0, float32(ypos)-o.y0
+	if x < 0 || y < 0 {
+		return -1
+	}
+	col, row := int(x/o.cellW), int(y/o.cellH)
+	if col >= o.cols || x-float32(col)*o.cellW > o.thumbW || y-float32(row)*o.cellH > o.thumbH {
+		return -1
+	}
+	if i := row*o.cols + col; i < len(slides) {
+		return i
+	}
+	return -1
+}
+
+func (o *overview) handleMouse(xpos, ypos float64) {
+	o.cursorX, o.cursorY = xpos, ypos
+	if i := o.at(xpos, ypos); i >= 0 {
+		o.selected = i
+	}
+}
+
+func (o *overview) click() {
+	if i := o.at(o.cursorX, o.cursorY); i >= 0 {
+		closeOverview(i)
+	}
+}
+
+// close keeps slide i running and closes the others, the resumed slides and the slide the
+// overview was opened from are suspended again. The slides that failed are already closed
+// and must stay out of the cache.
+func (o *overview) close(i int) {
+	for j, s := range slides {
+		if !o.open[j] || j == i || s == o.previous {
+			continue
+		}
+		if o.resumed[j] {
+			suspended.suspend(s)
+		} else {
+			closeSlide(s)
+		}
+	}
+	// the error slide isnt in the list, suspending it closes it
+	if p := sections.SlidePosition(slides, o.previous); o.previous != slides[i] && (p < 0 || o.open[p]) {
+		suspended.suspend(o.previous)
+	}
+	for _, fb := range o.thumbs {
+		if fb != nil {
+			fb.delete()
+		}
+	}
+	o.quads.delete()
+	o.lines.delete()
+
+	slideIndex = i
+	reloadErr = nil
+	if o.open[i] {
+		currentSlide = slides[i]
+		return
+	}
+	initSlide(slides[i])
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+// quadRenderer draws textures in rectangles given in window coordinates
+type quadRenderer struct {
+	program  uint32
+	vao, vbo uint32
+	size     int32
+}
+
+func newQuadRenderer() (*quadRenderer, error) {
+	var vertexShader = `
+	#version 330 core
+	layout (location = 0) in vec2 position;
+	layout (location = 1) in vec2 texCoord;
+	uniform vec2 size;
+	out vec2 uv;
+	void main() {
+		gl_Position = vec4(position.x / size.x * 2.0 - 1.0, 1.0 - position.y / size.y * 2.0, 0.0, 1.0);
+		uv = texCoord;
+	}` + "\x00"
+	var fragShader = `
+	#version 330 core
+	in vec2 uv;
+	uniform sampler2D tex;
+	out vec4 outColor;
+	void main() {
+		outColor = vec4(texture(tex, uv).rgb, 1.0);
+	}` + "\x00"
+
+	program, err := glutils.BasicProgram(vertexShader, fragShader)
+	if err != nil {
+		return nil, err
+	}
+	qr := &quadRenderer{
+		program: program,
+		size:    gl.GetUniformLocation(program, gl.Str("size\x00")),
+	}
+	gl.GenVertexArrays(1, &qr.vao)
+	gl.GenBuffers(1, &qr.vbo)
+	gl.BindVertexArray(qr.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, qr.vbo)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 4*glutils.GL_FLOAT32_SIZE, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 4*glutils.GL_FLOAT32_SIZE, gl.PtrOffset(2*glutils.GL_FLOAT32_SIZE))
+	gl.EnableVertexAttribArray(1)
+	gl.BindVertexArray(0)
+	return qr, nil
+}
+
+func (qr *quadRenderer) draw(texture uint32, x, y, w, h float32, vp sections.Viewport) {
+	// the rows of the texture go bottom up
+	quad := []float32{
+		x, y, 0, 1,
+		x, y + h, 0, 0,
+		x + w, y, 1, 1,
+		x + w, y + h, 1, 0,
+	}
+	gl.UseProgram(qr.program)
+	gl.Uniform2f(qr.size, float32(vp.WindowWidth), float32(vp.WindowHeight))
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	gl.BindVertexArray(qr.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, qr.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(quad)*glutils.GL_FLOAT32_SIZE, gl.Ptr(quad), gl.STREAM_DRAW)
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	gl.BindVertexArray(0)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+func (qr *quadRenderer) delete() {
+	gl.DeleteVertexArrays(1, &qr.vao)
+	gl.DeleteBuffers(1, &qr.vbo)
+	gl.DeleteProgram(qr.program)
+}
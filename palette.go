@@ -0,0 +1,163 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/raedatoui/glutils"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// paletteRows is the number of matches listed
+const paletteRows = 10
+
+// palette is an overlay listing the slides matching what is typed, by their header and
+// sub header. Enter goes to the selected one.
+type palette struct {
+	query    []rune
+	matches  []int
+	selected int
+	// opened is the frame the palette was opened in, the character of the key that opened it is dropped
+	opened int
+	lines  *lineRenderer
+}
+
+func openPalette() (*palette, error) {
+	lr, err := newLineRenderer()
+	if err != nil {
+		return nil, err
+	}
+	p := &palette{lines: lr, opened: frameCount}
+	p.filter()
+	return p, nil
+}
+
+func (p *palette) handleKey(k glfw.Key) {
+	switch k {
+	case glfw.KeyEscape:
+		closePalette()
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		if len(p.matches) > 0 {
+			i := p.matches[p.selected]
+			closePalette()
+			gotoSlide(i)
+		}
+	case glfw.KeyUp:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case glfw.KeyDown:
+		if p.selected < len(p.matches)-1 && p.selected < paletteRows-1 {
+			p.selected++
+		}
+	case glfw.KeyBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	}
+}
+
+func (p *palette) handleChar(r rune) {
+	if frameCount == p.opened {
+		return
+	}
+	p.query = append(p.query, r)
+	p.filter()
+}
+
+// filter ranks the slides matching the query, the slides matching by their header come
+// before the ones matching by their sub header
+func (p *palette) filter() {
+	type rank struct {
+		header bool
+		score  int
+	}
+	q := strings.ToLower(string(p.query))
+	ranks := make(map[int]rank)
+	p.matches = p.matches[:0]
+	for i, s := range slides {
+		score, ok := fuzzyScore(q, strings.ToLower(s.GetHeader()))
+		header := ok
+		if !ok {
+			if score, ok = fuzzyScore(q, strings.ToLower(s.GetSubHeader())); !ok {
+				continue
+			}
+		}
+		ranks[i] = rank{header, score}
+		p.matches = append(p.matches, i)
+	}
+	sort.SliceStable(p.matches, func(a, b int) bool {
+		ra, rb := ranks[p.matches[a]], ranks[p.matches[b]]
+		if ra.header != rb.header {
+			return ra.header
+		}
+		return ra.score > rb.score
+	})
+	p.selected = 0
+}
+
+// fuzzyScore matches the query as a subsequence of s. Consecutive letters and letters
+// starting a word score more, so "tex3" ranks "4c. Textures Ex3" first.
+func fuzzyScore(query, s string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(query)
+	score, qi, last := 0, 0, -2
+	prev := ' '
+	for i, r := range []rune(s) {
+		if qi < len(q) && r == q[qi] {
+			score++
+			if i == last+1 {
+				score += 5
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 10
+			}
+			last = i
+			qi++
+		}
+		prev = r
+	}
+	// the earlier the match ends, the better
+	return score*100 - last, qi == len(q)
+}
+
+func (p *palette) draw() {
+	w := float32(700)
+	x := (float32(viewport.WindowWidth) - w) / 2
+	y := float32(80)
+	rows := len(p.matches)
+	if rows > paletteRows {
+		rows = paletteRows
+	}
+	h := 50 + 40*float32(rows)
+
+	sections.OverlayRenderState().Apply()
+	p.lines.draw(gl.TRIANGLE_FAN, []float32{x, y, x + w, y, x + w, y + h, x, y + h}, glutils.Color32{R: 0.1, G: 0.1, B: 0.1, A: 0.9}, viewport)
+	if rows > 0 {
+		sy := y + 45 + 40*float32(p.selected)
+		p.lines.draw(gl.TRIANGLE_FAN, []float32{x, sy, x + w, sy, x + w, sy + 40, x, sy + 40}, glutils.Color32{R: 0.3, G: 0.3, B: 0.5, A: 0.9}, viewport)
+	}
+	sections.DefaultRenderState().Apply()
+
+	font.SetColor(1.0, 1.0, 1.0, 1.0)
+	font.Printf(x+15, y+32, 0.35, "> %s_", string(p.query))
+	for i := 0; i < rows; i++ {
+		s := slides[p.matches[i]]
+		ry := y + 45 + 40*float32(i)
+		font.SetColor(1.0, 1.0, 1.0, 1.0)
+		font.Printf(x+15, ry+20, 0.3, "%s", firstLine(s.GetHeader()))
+		font.SetColor(0.7, 0.7, 0.7, 1.0)
+		font.Printf(x+15, ry+35, 0.2, "%s", firstLine(s.GetSubHeader()))
+	}
+	font.SetColor(1.0, 1.0, 1.0, 1.0)
+}
+
+func (p *palette) delete() {
+	p.lines.delete()
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, s string
+		ok       bool
+	}{
+		{"", "anything", true},
+		{"", "", true},
+		{"tex3", "4c. textures ex3", true},
+		{"tex3", "4. textures", false},
+		{"abc", "ab", false},
+		{"ba", "ab", false},
+		{"hw", "1. hello window", true},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.s); ok != tt.ok {
+			t.Errorf("fuzzyScore(%q, %q) matched %v, want %v", tt.query, tt.s, ok, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	tests := []struct {
+		why                  string
+		query, better, worse string
+	}{
+		{"consecutive letters", "ex", "exit", "eaxt"},
+		{"start of a word", "ex", "my ex", "next"},
+		{"earlier end", "ab", "ab cd", "cd ab"},
+	}
+	for _, tt := range tests {
+		b, _ := fuzzyScore(tt.query, tt.better)
+		w, _ := fuzzyScore(tt.query, tt.worse)
+		if b <= w {
+			t.Errorf("%s: %q scores %d in %q and %d in %q", tt.why, tt.query, b, tt.better, w, tt.worse)
+		}
+	}
+}
+
+// headedSlide has a sub header to search
+type headedSlide struct {
+	sections.BaseSketch
+	sub string
+}
+
+func (s *headedSlide) GetSubHeader() string {
+	return s.sub
+}
+
+func TestPaletteFilter(t *testing.T) {
+	defer func(s []sections.Slide) { slides = s }(slides)
+	slides = nil
+	for _, h := range [][2]string{
+		{"1. Hello Window", "clear the window"},
+		{"4. Textures", "two textures mixed"},
+		{"4a. Textures Ex1", "flipped texture"},
+		{"4c. Textures Ex3", "scaled down"},
+		{"Mixing", "texture mix 3"},
+	} {
+		s := &headedSlide{sub: h[1]}
+		s.SetName(h[0])
+		slides = append(slides, s)
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{0, 1, 2, 3, 4}},
+		{"zzz", []int{}},
+		{"tex3", []int{3, 4}},
+		// the headers rank above the sub headers, the ties keep the slide order
+		{"tex", []int{1, 2, 3, 4}},
+		{"TEX", []int{1, 2, 3, 4}},
+		{"hw", []int{0}},
+		{"window", []int{0}},
+		{"flip", []int{2}},
+	}
+	for _, tt := range tests {
+		p := &palette{query: []rune(tt.query), selected: 2}
+		p.filter()
+		if got := append([]int{}, p.matches...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+		if p.selected != 0 {
+			t.Errorf("filter(%q) kept the selection %d", tt.query, p.selected)
+		}
+	}
+}
+
+func TestPaletteFilterRegistry(t *testing.T) {
+	defer func(s []sections.Slide) { slides = s }(slides)
+	slides = setupSlides()
+
+	tests := []struct{ query, first string }{
+		{"tex3", "4c. Textures Ex3"},
+		{"hello tri", "2a. Hello Triangle"},
+		{"camera", "7. Camera"},
+	}
+	for _, tt := range tests {
+		p := &palette{query: []rune(tt.query)}
+		p.filter()
+		if len(p.matches) == 0 {
+			t.Errorf("nothing matches %q", tt.query)
+			continue
+		}
+		if got := slides[p.matches[0]].GetHeader(); got != tt.first {
+			t.Errorf("%q ranks %q first, want %q", tt.query, got, tt.first)
+		}
+	}
+}
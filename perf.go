@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// queryFrames is how many frames the query results are read after they were issued,
+// reading them right away would make the CPU wait for the GPU
+const queryFrames = 4
+
+// gpuQueries measures the GPU time and the number of primitives of what is drawn between
+// begin and end, the results lag a few frames behind
+type gpuQueries struct {
+	time, primitives [queryFrames]uint32
+	frame            int
+	// GPUTime is the time in seconds the GPU spent on the last frame read
+	GPUTime float64
+	// Primitives is the number of primitives, the triangles of the slides, of the last frame read
+	Primitives int
+}
+
+func newGPUQueries() *gpuQueries {
+	q := &gpuQueries{}
+	gl.GenQueries(queryFrames, &q.time[0])
+	gl.GenQueries(queryFrames, &q.primitives[0])
+	return q
+}
+
+func (q *gpuQueries) begin() {
+	i := q.frame % queryFrames
+	gl.BeginQuery(gl.TIME_ELAPSED, q.time[i])
+	gl.BeginQuery(gl.PRIMITIVES_GENERATED, q.primitives[i])
+}
+
+// end ends the queries of the frame and reads the oldest ones, it returns true when
+// GPUTime and Primitives were updated
+func (q *gpuQueries) end() bool {
+	gl.EndQuery(gl.TIME_ELAPSED)
+	gl.EndQuery(gl.PRIMITIVES_GENERATED)
+	q.frame++
+	if q.frame < queryFrames {
+		return false
+	}
+
+	// the oldest query is the next one to be reused
+	i := q.frame % queryFrames
+	var available int32
+	gl.GetQueryObjectiv(q.time[i], gl.QUERY_RESULT_AVAILABLE, &available)
+	if available == 0 {
+		return false
+	}
+	var ns, prims uint64
+	gl.GetQueryObjectui64v(q.time[i], gl.QUERY_RESULT, &ns)
+	gl.GetQueryObjectui64v(q.primitives[i], gl.QUERY_RESULT, &prims)
+	q.GPUTime = float64(ns) / 1e9
+	q.Primitives = int(prims)
+	return true
+}
+
+func (q *gpuQueries) delete() {
+	gl.DeleteQueries(queryFrames, &q.time[0])
+	gl.DeleteQueries(queryFrames, &q.primitives[0])
+}
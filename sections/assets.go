@@ -0,0 +1,79 @@
+package sections
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// assetResolver maps the asset names used by the slides, like "images/container.png",
+// to files on disk. glutils and glfont only load from paths, so embedded assets are
+// extracted to a cache directory the first time they are requested.
+type assetResolver struct {
+	// dir is the directory with the assets on disk, or the cache directory for embedded ones
+	dir       string
+	embedded  fs.FS
+	extracted map[string]bool
+}
+
+var assets = &assetResolver{dir: "_assets"}
+
+// SetAssetDir makes the assets load from a directory on disk, the equivalent of _assets
+func SetAssetDir(dir string) {
+	assets = &assetResolver{dir: dir}
+}
+
+// SetAssetFS makes the assets load from a filesystem, like one embedded in the binary.
+// The files are extracted into cache as they are used.
+func SetAssetFS(fsys fs.FS, cache string) {
+	assets = &assetResolver{dir: cache, embedded: fsys, extracted: make(map[string]bool)}
+}
+
+// Asset returns the path on disk of an asset. Directories, like the ones holding a model
+// and its textures, are extracted as a whole. An asset that doesnt exist isnt logged, the
+// path doesnt exist either so the caller gets the error when opening it, and can ignore it
+// for an optional file. Only a failure to extract an existing asset is logged.
+func Asset(name string) string {
+	p := filepath.Join(assets.dir, filepath.FromSlash(name))
+	if assets.embedded == nil || assets.extracted[name] {
+		return p
+	}
+	if err := assets.extract(path.Clean(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("cant extract asset %s: %v", name, err)
+	}
+	assets.extracted[name] = true
+	return p
+}
+
+func (a *assetResolver) extract(name string) error {
+	return fs.WalkDir(a.embedded, name, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(a.dir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0755)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		src, err := a.embedded.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		out, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, src); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
@@ -0,0 +1,88 @@
+package sections
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetFS(t *testing.T) {
+	defer func(a *assetResolver) { assets = a }(assets)
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	cache := t.TempDir()
+	SetAssetFS(fstest.MapFS{
+		"images/container.png": {Data: []byte("png")},
+		"objects/box/box.obj":  {Data: []byte("obj")},
+		"objects/box/box.png":  {Data: []byte("texture")},
+		"slides/section-1.md":  {Data: []byte("# one")},
+		"shaders/unused.frag":  {Data: []byte("frag")},
+	}, cache)
+
+	read := func(p string) string {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		return string(b)
+	}
+	if p := Asset("images/container.png"); p != filepath.Join(cache, "images", "container.png") {
+		t.Errorf("path %s", p)
+	} else if got := read(p); got != "png" {
+		t.Errorf("extracted %q", got)
+	}
+	// a directory comes out with everything in it
+	dir := Asset("objects/box")
+	if got := read(filepath.Join(dir, "box.obj")) + read(filepath.Join(dir, "box.png")); got != "objtexture" {
+		t.Errorf("extracted %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "shaders")); !os.IsNotExist(err) {
+		t.Errorf("extracted an asset nobody asked for: %v", err)
+	}
+
+	// an optional file that isnt there is the callers business
+	p := Asset("slides/section-2.md")
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Errorf("missing asset: %v", err)
+	}
+	if logged.Len() != 0 {
+		t.Errorf("logged %q", logged.String())
+	}
+}
+
+func TestAssetFSExtractFails(t *testing.T) {
+	defer func(a *assetResolver) { assets = a }(assets)
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	// the cache is a file, nothing can be extracted under it
+	cache := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(cache, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetAssetFS(fstest.MapFS{"images/container.png": {Data: []byte("png")}}, cache)
+	Asset("images/container.png")
+	if logged.Len() == 0 {
+		t.Error("the failed extraction wasnt logged")
+	}
+	// and it isnt retried every frame
+	logged.Reset()
+	Asset("images/container.png")
+	if logged.Len() != 0 {
+		t.Errorf("logged again %q", logged.String())
+	}
+}
+
+func TestAssetDir(t *testing.T) {
+	defer func(a *assetResolver) { assets = a }(assets)
+	SetAssetDir("somewhere")
+	if p := Asset("images/container.png"); p != filepath.Join("somewhere", "images", "container.png") {
+		t.Errorf("path %s", p)
+	}
+}
@@ -2,25 +2,19 @@ package sections
 
 import (
 	"errors"
+	"log"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/raedatoui/glfont"
 	"github.com/raedatoui/glutils"
 )
 
-// WIDTH is the width of the window
-var WIDTH = 1280.0
-
-// HEIGHT is the height of the window
-var HEIGHT = 1024.0
-
-var Ratio = float32(WIDTH / HEIGHT)
-
 // Slide is the most basic slide. it has to setup, update, draw and close
 type Slide interface {
-	Init(a ...interface{}) error
+	Init(ctx Context) error
 	InitGL() error
 	Update()
-	Draw()
+	Draw(vp Viewport)
 	Close()
 	GetHeader() string
 	GetSubHeader() string
@@ -31,16 +25,61 @@ type Slide interface {
 	HandleMousePosition(xpos, ypos float64)
 	HandleScroll(xoff, yoff float64)
 	HandleFiles(names []string)
+	ReloadShaders() (bool, error)
+	ReleaseResources()
+	RenderState() RenderState
 	DrawText() bool
 }
 
+// Suspender is implemented by slides that want to know when they are left but kept running
+// in the cache, Suspend, and when they come back instead of going through InitGL, Resume.
+// Their GL objects stay alive until Close.
+type Suspender interface {
+	Suspend()
+	Resume()
+}
+
 // BaseSlide is the base implementation of Slide with the min required fields
 type BaseSlide struct {
 	Slide
-	Name     string
-	Color    glutils.Color
-	Color32  glutils.Color32
-	ColorHex string
+	Name      string
+	Clock     *Clock
+	Font      *glfont.Font
+	Log       *log.Logger
+	Color     glutils.Color
+	Color32   glutils.Color32
+	ColorHex  string
+	assets    func(name string) string
+	shaders   map[*glutils.Shader]*watchedShader
+	resources []resource
+	debug     bool
+}
+
+// Init keeps what the slide needs from the context. Slides overriding it call it first.
+func (s *BaseSlide) Init(ctx Context) error {
+	if ctx.Clock == nil {
+		return errors.New("the context has no clock")
+	}
+	s.Clock = ctx.Clock
+	s.Font = ctx.Font
+	s.Log = ctx.Logger
+	if s.Log == nil {
+		s.Log = log.New(log.Writer(), "", log.Flags())
+	}
+	s.debug = ctx.Debug
+	s.assets = ctx.Assets
+	if s.assets == nil {
+		s.assets = Asset
+	}
+	s.Color = ctx.Color
+	s.Color32 = ctx.Color.To32()
+	s.ColorHex = glutils.Rgb2Hex(ctx.Color)
+	return nil
+}
+
+// Asset returns the path of an asset with the resolver of the context
+func (s *BaseSlide) Asset(name string) string {
+	return s.assets(name)
 }
 
 func (s *BaseSlide) GetHeader() string {
@@ -55,6 +94,10 @@ func (s *BaseSlide) SetName(n string) {
 	s.Name = n
 }
 
+func (s *BaseSlide) GetName() string {
+	return s.Name
+}
+
 func (s *BaseSlide) GetColorHex() string {
 	return s.ColorHex
 }
@@ -67,7 +110,7 @@ func (s *BaseSlide) Update() {
 
 }
 
-func (s *BaseSlide) Draw() {
+func (s *BaseSlide) Draw(vp Viewport) {
 
 }
 
@@ -91,19 +134,15 @@ func (s *BaseSlide) HandleFiles(names []string) {
 
 }
 
-type BaseSketch struct {
-	BaseSlide
+// RenderState is the default state cleared with the color of the slide
+func (s *BaseSlide) RenderState() RenderState {
+	r := DefaultRenderState()
+	r.ClearColor = s.Color32
+	return r
 }
 
-func (b *BaseSketch) Init(a ...interface{}) error {
-	c, ok := a[1].(glutils.Color)
-	if ok == false {
-		return errors.New("first argument isnt a color")
-	}
-	b.Color = c
-	b.Color32 = c.To32()
-	b.ColorHex = glutils.Rgb2Hex(c)
-	return nil
+type BaseSketch struct {
+	BaseSlide
 }
 
 func (b *BaseSketch) DrawText() bool {
@@ -0,0 +1,429 @@
+package sections
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/raedatoui/glutils"
+)
+
+// CameraMode is the way the mouse and keyboard move a CameraController
+type CameraMode int
+
+const (
+	// FirstPerson flies around with WSAD and looks around with the mouse
+	FirstPerson CameraMode = iota
+	// Orbit turns around the target keeping the world up, WS move closer or further
+	Orbit
+	// Arcball rolls the view around the target like a trackball
+	Arcball
+)
+
+func (m CameraMode) String() string {
+	switch m {
+	case Orbit:
+		return "orbit"
+	case Arcball:
+		return "arcball"
+	}
+	return "first person"
+}
+
+// CursorCapturer is implemented by slides that want the mouse cursor hidden and grabbed
+type CursorCapturer interface {
+	CursorCaptured() bool
+}
+
+// CameraControls is implemented by the slides with a camera, the presentation calls them
+// from the camera actions of the key bindings
+type CameraControls interface {
+	CycleMode()
+	ToggleCapture()
+	Fit()
+}
+
+// cameraKeys are the names of the keys bound to the camera actions, for Help. They start
+// as the default bindings.
+var cameraKeys = struct {
+	mode, cursor, fit string
+}{"M", "C", "F"}
+
+// SetCameraKeys sets the keys Help shows for the camera actions, an empty name leaves the
+// action out
+func SetCameraKeys(mode, cursor, fit string) {
+	cameraKeys.mode, cameraKeys.cursor, cameraKeys.fit = mode, cursor, fit
+}
+
+// CameraController drives a glutils.Camera from the input callbacks of a slide. Slides embed it
+// to get the HandleKeyboard, HandleMousePosition and HandleScroll implementations and call
+// UpdateCamera from their Update. The keys bound to the camera actions cycle the modes,
+// toggle the cursor capture and zoom to fit the bounds set with SetBounds.
+type CameraController struct {
+	Camera glutils.Camera
+	Mode   CameraMode
+	// Target is the point the orbit and arcball modes turn around
+	Target mgl32.Vec3
+
+	viewport     Viewport
+	distance     float64
+	orientation  mgl32.Quat
+	bounds       [2]mgl32.Vec3
+	hasBounds    bool
+	w, a, s, d   bool
+	lastX, lastY float64
+	firstMouse   bool
+	captured     bool
+	// home is the camera as created, for ResetState
+	home glutils.Camera
+}
+
+// NewCameraController creates a first person controller for a camera, the orbit target
+// is set one unit in front of it.
+func NewCameraController(c glutils.Camera) CameraController {
+	return CameraController{
+		Camera:      c,
+		home:        c,
+		Target:      c.Position.Add(c.Front),
+		distance:    1.0,
+		orientation: mgl32.QuatIdent(),
+		firstMouse:  true,
+	}
+}
+
+// SetBounds sets the box Fit frames
+func (cc *CameraController) SetBounds(min, max mgl32.Vec3) {
+	cc.bounds = [2]mgl32.Vec3{min, max}
+	cc.hasBounds = true
+}
+
+// SetMode switches mode keeping the camera where it is
+func (cc *CameraController) SetMode(m CameraMode) {
+	switch m {
+	case FirstPerson:
+		cc.syncAngles()
+	case Orbit:
+		cc.distance = float64(cc.Target.Sub(cc.Camera.Position).Len())
+		cc.syncAngles()
+		cc.placeOrbit()
+	case Arcball:
+		cc.distance = float64(cc.Target.Sub(cc.Camera.Position).Len())
+		cc.orientation = mgl32.Mat4ToQuat(mgl32.LookAtV(cc.Camera.Position, cc.Target, cc.Camera.Up).Inv())
+		cc.placeArcball()
+	}
+	cc.Mode = m
+	cc.firstMouse = true
+}
+
+// ZoomToFit moves the camera back along its view direction until the box fits in the view
+// and makes its center the target.
+func (cc *CameraController) ZoomToFit(min, max mgl32.Vec3) {
+	center := min.Add(max).Mul(0.5)
+	radius := float64(max.Sub(min).Len()) / 2
+	// the projection is built from the Zoom field as is, see Projection
+	t := math.Abs(math.Tan(cc.Camera.Zoom / 2))
+	half := math.Min(math.Atan(t), math.Atan(t*float64(cc.viewport.Aspect())))
+	cc.Target = center
+	cc.distance = radius / math.Sin(half)
+
+	switch cc.Mode {
+	case Orbit:
+		cc.placeOrbit()
+	case Arcball:
+		cc.placeArcball()
+	default:
+		cc.Camera.Position = center.Sub(cc.Camera.Front.Mul(float32(cc.distance)))
+	}
+}
+
+// Projection returns the perspective matrix for the camera zoom
+func (cc *CameraController) Projection(near, far float32) mgl32.Mat4 {
+	return mgl32.Perspective(float32(cc.Camera.Zoom), cc.viewport.Aspect(), near, far)
+}
+
+// HandleResize keeps the viewport for the projection and the arcball, slides embedding
+// the controller and overriding it must call it
+func (cc *CameraController) HandleResize(vp Viewport) {
+	cc.viewport = vp
+}
+
+// CursorCaptured reports whether the cursor was grabbed with ToggleCapture
+func (cc *CameraController) CursorCaptured() bool {
+	return cc.captured
+}
+
+// CycleMode switches to the next mode
+func (cc *CameraController) CycleMode() {
+	cc.SetMode((cc.Mode + 1) % 3)
+}
+
+// ToggleCapture grabs or releases the cursor
+func (cc *CameraController) ToggleCapture() {
+	cc.captured = !cc.captured
+	cc.firstMouse = true
+}
+
+// Fit zooms to fit the bounds, if they were set
+func (cc *CameraController) Fit() {
+	if cc.hasBounds {
+		cc.ZoomToFit(cc.bounds[0], cc.bounds[1])
+	}
+}
+
+// Help describes the mode and the keys, for the sub header of the slide
+func (cc *CameraController) Help() string {
+	h := cc.Mode.String() + " camera: WSAD and mouse"
+	if k := cameraKeys.mode; k != "" {
+		h += ", " + k + " mode"
+	}
+	if k := cameraKeys.cursor; k != "" {
+		h += ", " + k + " cursor"
+	}
+	if k := cameraKeys.fit; k != "" && cc.hasBounds {
+		h += ", " + k + " fit"
+	}
+	return h
+}
+
+// UpdateCamera applies the movement keys held down during the last frame, delta is
+// Clock.FrameDelta so pausing the animations doesnt freeze the camera
+func (cc *CameraController) UpdateCamera(delta float64) {
+	if cc.Mode == FirstPerson {
+		if cc.w {
+			cc.Camera.ProcessKeyboard(glutils.FORWARD, delta)
+		}
+		if cc.s {
+			cc.Camera.ProcessKeyboard(glutils.BACKWARD, delta)
+		}
+		if cc.a {
+			cc.Camera.ProcessKeyboard(glutils.LEFT, delta)
+		}
+		if cc.d {
+			cc.Camera.ProcessKeyboard(glutils.RIGHT, delta)
+		}
+		return
+	}
+
+	step := cc.Camera.MovementSpeed * delta
+	if cc.w {
+		cc.dolly(1 - step/4)
+	}
+	if cc.s {
+		cc.dolly(1 + step/4)
+	}
+	if cc.Mode == Orbit {
+		if cc.a {
+			cc.Camera.Yaw += step * 20
+		}
+		if cc.d {
+			cc.Camera.Yaw -= step * 20
+		}
+		cc.placeOrbit()
+	}
+}
+
+func (cc *CameraController) HandleKeyboard(k glfw.Key, s int, a glfw.Action, mk glfw.ModifierKey, keys map[glfw.Key]bool) {
+	cc.w = keys[glfw.KeyW]
+	cc.a = keys[glfw.KeyA]
+	cc.s = keys[glfw.KeyS]
+	cc.d = keys[glfw.KeyD]
+}
+
+func (cc *CameraController) HandleMousePosition(xpos, ypos float64) {
+	if cc.firstMouse {
+		cc.lastX = xpos
+		cc.lastY = ypos
+		cc.firstMouse = false
+	}
+
+	xoffset := xpos - cc.lastX
+	yoffset := cc.lastY - ypos // Reversed since y-coordinates go from bottom to left
+
+	switch cc.Mode {
+	case FirstPerson:
+		cc.Camera.ProcessMouseMovement(xoffset, yoffset, true)
+	case Orbit:
+		cc.Camera.Yaw += xoffset * cc.Camera.MouseSensitivity
+		cc.Camera.Pitch = clamp(cc.Camera.Pitch-yoffset*cc.Camera.MouseSensitivity, -89, 89)
+		cc.placeOrbit()
+	case Arcball:
+		cc.roll(cc.lastX, cc.lastY, xpos, ypos)
+		cc.placeArcball()
+	}
+
+	cc.lastX = xpos
+	cc.lastY = ypos
+}
+
+func (cc *CameraController) HandleScroll(xoff, yoff float64) {
+	if cc.Mode == FirstPerson {
+		cc.Camera.ProcessMouseScroll(yoff)
+		return
+	}
+	cc.dolly(1 - yoff/10)
+}
+
+func (cc *CameraController) dolly(f float64) {
+	cc.distance = clamp(cc.distance*f, 0.1, 1000)
+	if cc.Mode == Arcball {
+		cc.placeArcball()
+	}
+}
+
+// syncAngles sets yaw and pitch from the front vector, so the first person mode
+// carries on from where the other modes left the camera
+func (cc *CameraController) syncAngles() {
+	f := cc.Camera.Front
+	cc.Camera.Pitch = float64(mgl32.RadToDeg(float32(math.Asin(float64(f.Y())))))
+	cc.Camera.Yaw = float64(mgl32.RadToDeg(float32(math.Atan2(float64(f.Z()), float64(f.X())))))
+}
+
+// placeOrbit puts the camera on the sphere around the target at the yaw and pitch angles
+func (cc *CameraController) placeOrbit() {
+	yaw := float64(mgl32.DegToRad(float32(cc.Camera.Yaw)))
+	pitch := float64(mgl32.DegToRad(float32(cc.Camera.Pitch)))
+	front := mgl32.Vec3{
+		float32(math.Cos(yaw) * math.Cos(pitch)),
+		float32(math.Sin(pitch)),
+		float32(math.Sin(yaw) * math.Cos(pitch)),
+	}.Normalize()
+	cc.Camera.Position = cc.Target.Sub(front.Mul(float32(cc.distance)))
+	cc.setAxes(front, cc.Camera.WorldUp)
+}
+
+// placeArcball puts the camera behind the target along the orientation
+func (cc *CameraController) placeArcball() {
+	cc.Camera.Position = cc.Target.Add(cc.orientation.Rotate(mgl32.Vec3{0, 0, float32(cc.distance)}))
+	cc.setAxes(cc.orientation.Rotate(mgl32.Vec3{0, 0, -1}), cc.orientation.Rotate(mgl32.Vec3{0, 1, 0}))
+}
+
+func (cc *CameraController) setAxes(front, up mgl32.Vec3) {
+	cc.Camera.Front = front
+	cc.Camera.Right = front.Cross(up).Normalize()
+	cc.Camera.Up = cc.Camera.Right.Cross(front).Normalize()
+}
+
+// roll turns the orientation by the rotation between the two cursor positions projected
+// on a sphere filling the window
+func (cc *CameraController) roll(x0, y0, x1, y1 float64) {
+	p0 := cc.arcballPoint(x0, y0)
+	p1 := cc.arcballPoint(x1, y1)
+	angle := math.Acos(math.Min(1, float64(p0.Dot(p1))))
+	axis := p0.Cross(p1)
+	if angle < 1e-6 || axis.Len() < 1e-6 {
+		return
+	}
+	// the axis is in view space, dragging turns the scene so the camera goes the other way
+	world := cc.orientation.Rotate(axis.Normalize())
+	cc.orientation = mgl32.QuatRotate(float32(-angle), world).Mul(cc.orientation).Normalize()
+}
+
+func (cc *CameraController) arcballPoint(x, y float64) mgl32.Vec3 {
+	// the cursor positions are in screen coordinates
+	w, h := float64(cc.viewport.WindowWidth), float64(cc.viewport.WindowHeight)
+	if w == 0 || h == 0 {
+		return mgl32.Vec3{0, 0, 1}
+	}
+	p := mgl32.Vec3{
+		float32(2*x/w - 1),
+		float32(1 - 2*y/h),
+		0,
+	}
+	if d := p.X()*p.X() + p.Y()*p.Y(); d <= 1 {
+		p[2] = float32(math.Sqrt(float64(1 - d)))
+		return p
+	}
+	return p.Normalize()
+}
+
+// cameraState is the pose of the camera saved between runs
+type cameraState struct {
+	Mode        CameraMode `json:"mode"`
+	Position    mgl32.Vec3 `json:"position"`
+	Yaw         float64    `json:"yaw"`
+	Pitch       float64    `json:"pitch"`
+	Zoom        float64    `json:"zoom"`
+	Target      mgl32.Vec3 `json:"target"`
+	Distance    float64    `json:"distance"`
+	Orientation [4]float32 `json:"orientation"`
+}
+
+// Suspend stops the camera, the keys released while the slide was away never reach it
+func (cc *CameraController) Suspend() {
+	cc.w, cc.a, cc.s, cc.d = false, false, false, false
+	cc.firstMouse = true
+}
+
+// Resume does nothing, the camera is where it was left
+func (cc *CameraController) Resume() {
+}
+
+// SaveState returns the pose of the camera, slides embedding the controller are Stateful
+func (cc *CameraController) SaveState() (json.RawMessage, error) {
+	o := cc.orientation
+	return json.Marshal(cameraState{
+		Mode:        cc.Mode,
+		Position:    cc.Camera.Position,
+		Yaw:         cc.Camera.Yaw,
+		Pitch:       cc.Camera.Pitch,
+		Zoom:        cc.Camera.Zoom,
+		Target:      cc.Target,
+		Distance:    cc.distance,
+		Orientation: [4]float32{o.W, o.V[0], o.V[1], o.V[2]},
+	})
+}
+
+// LoadState puts the camera back where SaveState found it
+func (cc *CameraController) LoadState(data json.RawMessage) error {
+	var st cameraState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return err
+	}
+	cc.Mode = st.Mode
+	cc.Camera.Position = st.Position
+	cc.Camera.Yaw = st.Yaw
+	cc.Camera.Pitch = st.Pitch
+	cc.Camera.Zoom = st.Zoom
+	cc.Target = st.Target
+	cc.distance = st.Distance
+	cc.orientation = mgl32.Quat{W: st.Orientation[0], V: mgl32.Vec3{st.Orientation[1], st.Orientation[2], st.Orientation[3]}}
+	if cc.orientation.Len() == 0 {
+		cc.orientation = mgl32.QuatIdent()
+	}
+
+	switch cc.Mode {
+	case Orbit:
+		cc.placeOrbit()
+	case Arcball:
+		cc.placeArcball()
+	default:
+		cc.Mode = FirstPerson
+		cc.aim()
+	}
+	cc.firstMouse = true
+	return nil
+}
+
+// ResetState puts the camera back where it was created
+func (cc *CameraController) ResetState() {
+	reset := NewCameraController(cc.home)
+	reset.viewport, reset.bounds, reset.hasBounds = cc.viewport, cc.bounds, cc.hasBounds
+	*cc = reset
+}
+
+// aim points the camera at the yaw and pitch angles, like glutils does in first person
+func (cc *CameraController) aim() {
+	yaw := float64(mgl32.DegToRad(float32(cc.Camera.Yaw)))
+	pitch := float64(mgl32.DegToRad(float32(cc.Camera.Pitch)))
+	front := mgl32.Vec3{
+		float32(math.Cos(yaw) * math.Cos(pitch)),
+		float32(math.Sin(pitch)),
+		float32(math.Sin(yaw) * math.Cos(pitch)),
+	}.Normalize()
+	cc.setAxes(front, cc.Camera.WorldUp)
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,104 @@
+package sections
+
+import "strconv"
+
+// Clock is the time source of the slides. The main loop ticks it once per frame and
+// slides read the simulated time from it instead of the wall clock, so animations
+// can be paused, stepped, slowed down or replayed at a fixed rate.
+type Clock struct {
+	source      func() float64
+	last        float64
+	time, delta float64
+	frame       float64
+	paused      bool
+	step        bool
+	// Scale multiplies the elapsed real time, 0.5 plays at half speed
+	Scale float64
+	// FixedStep, when positive, is the amount of time every tick advances by, regardless of the real time
+	FixedStep float64
+}
+
+// NewClock creates a running clock reading the real time from source, like glfw.GetTime.
+func NewClock(source func() float64) *Clock {
+	c := &Clock{
+		source: source,
+		Scale:  1.0,
+	}
+	c.last = source()
+	return c
+}
+
+// Tick advances the clock by one frame. It is called by the main loop before updating the slide.
+func (c *Clock) Tick() {
+	now := c.source()
+	elapsed := now - c.last
+	c.last = now
+	c.frame = elapsed
+	if c.FixedStep > 0 {
+		c.frame = c.FixedStep
+	}
+
+	if c.paused && !c.step {
+		c.delta = 0
+		return
+	}
+	c.step = false
+
+	if c.FixedStep > 0 {
+		c.delta = c.FixedStep
+	} else {
+		c.delta = elapsed * c.Scale
+	}
+	c.time += c.delta
+}
+
+// Time returns the simulated time in seconds
+func (c *Clock) Time() float64 {
+	return c.time
+}
+
+// Delta returns the simulated time elapsed during the last frame
+func (c *Clock) Delta() float64 {
+	return c.delta
+}
+
+// FrameDelta returns the duration of the last frame, ignoring the pause and the scale. The
+// camera moves by it, so it can be navigated while the animations are paused or slowed down.
+// With a fixed step it is the step, replays then move the camera the same way.
+func (c *Clock) FrameDelta() float64 {
+	return c.frame
+}
+
+// Set jumps to the given simulated time. The next frame has no elapsed time.
+func (c *Clock) Set(t float64) {
+	c.time = t
+	c.delta = 0
+	c.frame = 0
+	c.last = c.source()
+}
+
+func (c *Clock) Paused() bool {
+	return c.paused
+}
+
+func (c *Clock) TogglePause() {
+	c.paused = !c.paused
+}
+
+// Step advances a paused clock by a single frame on the next tick
+func (c *Clock) Step() {
+	c.step = true
+}
+
+// String describes the clock state for the footer, it is empty while running at normal speed
+func (c *Clock) String() string {
+	switch {
+	case c.paused:
+		return "PAUSED"
+	case c.FixedStep > 0:
+		return "FIXED"
+	case c.Scale != 1.0:
+		return "x" + strconv.FormatFloat(c.Scale, 'f', 2, 64)
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package sections
+
+import "testing"
+
+// fakeTime is a real time source the tests move by hand
+type fakeTime struct {
+	now float64
+}
+
+func (f *fakeTime) get() float64 {
+	return f.now
+}
+
+func TestClock(t *testing.T) {
+	type tick struct {
+		// now is the real time of the tick, before runs at that time just before it
+		now    float64
+		before func(c *Clock)
+		// time, delta and frame are the clock readings after the tick
+		time, delta, frame float64
+	}
+	pause := func(c *Clock) { c.TogglePause() }
+	step := func(c *Clock) { c.Step() }
+
+	tests := []struct {
+		name  string
+		setup func(c *Clock)
+		ticks []tick
+	}{
+		{"running", nil, []tick{
+			{now: 0.5, time: 0.5, delta: 0.5, frame: 0.5},
+			{now: 0.75, time: 0.75, delta: 0.25, frame: 0.25},
+		}},
+		{"paused", nil, []tick{
+			{now: 0.5, time: 0.5, delta: 0.5, frame: 0.5},
+			{now: 1, before: pause, time: 0.5, delta: 0, frame: 0.5},
+			{now: 1.25, time: 0.5, delta: 0, frame: 0.25},
+			{now: 1.5, before: pause, time: 0.75, delta: 0.25, frame: 0.25},
+		}},
+		{"step", func(c *Clock) { c.TogglePause() }, []tick{
+			{now: 0.5, time: 0, delta: 0, frame: 0.5},
+			{now: 0.75, before: step, time: 0.25, delta: 0.25, frame: 0.25},
+			{now: 1, time: 0.25, delta: 0, frame: 0.25},
+		}},
+		{"step while running does nothing more", nil, []tick{
+			{now: 0.5, before: step, time: 0.5, delta: 0.5, frame: 0.5},
+			{now: 0.75, time: 0.75, delta: 0.25, frame: 0.25},
+		}},
+		{"scale", func(c *Clock) { c.Scale = 0.5 }, []tick{
+			{now: 1, time: 0.5, delta: 0.5, frame: 1},
+			{now: 1.5, before: func(c *Clock) { c.Scale = 2 }, time: 1.5, delta: 1, frame: 0.5},
+		}},
+		{"fixed step", func(c *Clock) { c.FixedStep = 0.125 }, []tick{
+			{now: 1, time: 0.125, delta: 0.125, frame: 0.125},
+			{now: 1.01, time: 0.25, delta: 0.125, frame: 0.125},
+		}},
+		{"fixed step ignores the scale", func(c *Clock) { c.FixedStep = 0.125; c.Scale = 4 }, []tick{
+			{now: 1, time: 0.125, delta: 0.125, frame: 0.125},
+		}},
+		{"fixed step paused", func(c *Clock) { c.FixedStep = 0.125; c.TogglePause() }, []tick{
+			{now: 1, time: 0, delta: 0, frame: 0.125},
+			{now: 2, before: step, time: 0.125, delta: 0.125, frame: 0.125},
+		}},
+		{"set", nil, []tick{
+			{now: 0.5, time: 0.5, delta: 0.5, frame: 0.5},
+			{now: 1, before: func(c *Clock) { c.Set(10) }, time: 10, delta: 0, frame: 0},
+			{now: 1.25, time: 10.25, delta: 0.25, frame: 0.25},
+		}},
+	}
+	for _, tt := range tests {
+		src := &fakeTime{}
+		c := NewClock(src.get)
+		if tt.setup != nil {
+			tt.setup(c)
+		}
+		for i, tk := range tt.ticks {
+			src.now = tk.now
+			if tk.before != nil {
+				tk.before(c)
+			}
+			c.Tick()
+			if c.Time() != tk.time || c.Delta() != tk.delta || c.FrameDelta() != tk.frame {
+				t.Errorf("%s: tick %d: time %g, delta %g, frame %g, want %g, %g, %g", tt.name, i,
+					c.Time(), c.Delta(), c.FrameDelta(), tk.time, tk.delta, tk.frame)
+			}
+		}
+	}
+}
+
+func TestClockString(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c *Clock)
+		want  string
+	}{
+		{"running", func(c *Clock) {}, ""},
+		{"paused", func(c *Clock) { c.TogglePause() }, "PAUSED"},
+		{"paused with a fixed step", func(c *Clock) { c.TogglePause(); c.FixedStep = 0.1 }, "PAUSED"},
+		{"fixed step", func(c *Clock) { c.FixedStep = 0.1; c.Scale = 2 }, "FIXED"},
+		{"scale", func(c *Clock) { c.Scale = 0.5 }, "x0.50"},
+	}
+	for _, tt := range tests {
+		c := NewClock((&fakeTime{}).get)
+		tt.setup(c)
+		if got := c.String(); got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+		if c.Paused() != (tt.want == "PAUSED") {
+			t.Errorf("%s: Paused() = %v", tt.name, c.Paused())
+		}
+	}
+}
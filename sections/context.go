@@ -0,0 +1,25 @@
+package sections
+
+import (
+	"log"
+
+	"github.com/raedatoui/glfont"
+	"github.com/raedatoui/glutils"
+)
+
+// Context is everything a slide is built with, it is passed to Init
+type Context struct {
+	Font  *glfont.Font
+	Color glutils.Color
+	// Title is the text shown by title slides, other slides take their header from the registry
+	Title string
+	// Assets resolves asset names like "images/container.png" to paths, Asset when nil
+	Assets func(name string) string
+	Clock  *Clock
+	// Viewport is the viewport when the slide is built, the current one is passed to Draw
+	Viewport Viewport
+	// Logger is used by the slides to report problems that dont stop them, the standard logger when nil
+	Logger *log.Logger
+	// Debug makes the slides log the GL objects they leave behind when they close
+	Debug bool
+}
@@ -0,0 +1,41 @@
+package sections
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/glutils"
+)
+
+// drawCalls counts the draw calls made through the functions below, for the performance HUD
+var drawCalls int
+
+// DrawArrays is gl.DrawArrays counted by the HUD
+func DrawArrays(mode uint32, first, count int32) {
+	drawCalls++
+	gl.DrawArrays(mode, first, count)
+}
+
+// DrawElements is gl.DrawElements counted by the HUD
+func DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {
+	drawCalls++
+	gl.DrawElements(mode, count, xtype, indices)
+}
+
+// DrawModel draws a model with its program, it counts a draw call per mesh of a model loaded
+// with LoadModel and one for the others
+func DrawModel(m *glutils.Model, program uint32) {
+	if n := models[m].meshes; n > 0 {
+		drawCalls += n
+	} else {
+		drawCalls++
+	}
+	m.Draw(program)
+}
+
+// ResetDrawCalls returns the number of draw calls since the last reset
+func ResetDrawCalls() int {
+	n := drawCalls
+	drawCalls = 0
+	return n
+}
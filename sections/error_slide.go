@@ -0,0 +1,63 @@
+package sections
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// maxLineLength is the number of characters after which error lines are wrapped
+const maxLineLength = 110
+
+// ErrorSlide is shown in place of a slide that failed to initialize or panicked.
+// It displays the error, which includes the compile log when a shader failed.
+type ErrorSlide struct {
+	BaseSlide
+	lines []string
+}
+
+func (s *ErrorSlide) Init(ctx Context) error {
+	if err := s.BaseSlide.Init(ctx); err != nil {
+		return err
+	}
+	if s.Font == nil {
+		return errors.New("the error slide needs a font")
+	}
+	return nil
+}
+
+// SetError sets the name of the failing slide and the error to display
+func (s *ErrorSlide) SetError(name string, err error) {
+	s.Name = "Failed: " + name
+	s.lines = nil
+	for _, l := range strings.Split(strings.Replace(err.Error(), "\t", "    ", -1), "\n") {
+		for len(l) > maxLineLength {
+			s.lines = append(s.lines, l[:maxLineLength])
+			l = l[maxLineLength:]
+		}
+		s.lines = append(s.lines, l)
+	}
+}
+
+func (s *ErrorSlide) Draw(vp Viewport) {
+	gl.ClearColor(s.Color32.R, s.Color32.G, s.Color32.B, s.Color32.A)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	s.Font.SetColor(1.0, 1.0, 1.0, 1.0)
+	for i := 0; i < len(s.lines); i++ {
+		y := 100 + 20*float32(i)
+		if y > float32(vp.WindowHeight)-40 {
+			break
+		}
+		s.Font.Printf(30, y, 0.3, s.lines[i])
+	}
+}
+
+func (s *ErrorSlide) GetSubHeader() string {
+	return "move to another slide to continue"
+}
+
+func (s *ErrorSlide) DrawText() bool {
+	return true
+}
@@ -7,9 +7,7 @@ package getstarted
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
 	_ "image/png"
 )
@@ -17,18 +15,17 @@ import (
 // HelloCube  Renders a textured spinning cube using GLFW 3 and OpenGL 4.1 core forward-compatible profile.
 type HelloCube struct {
 	sections.BaseSketch
-	program             uint32
-	vao, vbo            uint32
-	texture             uint32
-	angle, previousTime float64
-	model               mgl32.Mat4
-	modelUniform        int32
+	program      uint32
+	vao, vbo     uint32
+	texture      uint32
+	angle        float64
+	model        mgl32.Mat4
+	modelUniform int32
 }
 
 // Setup is inherited
 func (hc *HelloCube) InitGL() error {
 	hc.angle = 0.0
-	hc.Name = "0. Test Cube From github.com/go-gl/examples"
 
 	var cubeVertices = []float32{
 		//  X, Y, Z, U, V
@@ -114,17 +111,13 @@ func (hc *HelloCube) InitGL() error {
 	` + "\x00"
 	// Configure the vertex and fragment shaders
 	var err error
-	hc.program, err = glutils.BasicProgram(vertexShader, fragmentShader)
+	hc.program, err = hc.NewProgram(vertexShader, fragmentShader)
 	if err != nil {
 		return err
 	}
 
 	gl.UseProgram(hc.program)
 
-	projection := mgl32.Perspective(mgl32.DegToRad(45.0), sections.Ratio, 0.1, 10.0)
-	projectionUniform := gl.GetUniformLocation(hc.program, gl.Str("projection\x00"))
-	gl.UniformMatrix4fv(projectionUniform, 1, false, &projection[0])
-
 	camera := mgl32.LookAtV(mgl32.Vec3{3, 3, 3}, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0})
 	cameraUniform := gl.GetUniformLocation(hc.program, gl.Str("camera\x00"))
 	gl.UniformMatrix4fv(cameraUniform, 1, false, &camera[0])
@@ -139,16 +132,16 @@ func (hc *HelloCube) InitGL() error {
 	gl.BindFragDataLocation(hc.program, 0, gl.Str("outputColor\x00"))
 
 	// Load the texture
-	hc.texture, err = glutils.NewTexture(gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, gl.LINEAR, gl.LINEAR, "_assets/getting_started/0.cube/square.png")
+	hc.texture, err = hc.NewTexture(gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, gl.LINEAR, gl.LINEAR, hc.Asset("getting_started/0.cube/square.png"))
 	if err != nil {
 		return err
 	}
 
 	// Configure the vertex data
-	gl.GenVertexArrays(1, &hc.vao)
+	hc.vao = hc.GenVertexArray()
 	gl.BindVertexArray(hc.vao)
 
-	gl.GenBuffers(1, &hc.vbo)
+	hc.vbo = hc.GenBuffer()
 	gl.BindBuffer(gl.ARRAY_BUFFER, hc.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(cubeVertices)*4, gl.Ptr(cubeVertices), gl.STATIC_DRAW)
 
@@ -160,23 +153,25 @@ func (hc *HelloCube) InitGL() error {
 	gl.EnableVertexAttribArray(texCoordAttrib)
 	gl.VertexAttribPointer(texCoordAttrib, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
 
-	hc.previousTime = glfw.GetTime()
-
 	return nil
 }
 
+// HandleResize sets the projection, it only changes with the aspect of the viewport
+func (hc *HelloCube) HandleResize(vp sections.Viewport) {
+	gl.UseProgram(hc.program)
+	projection := mgl32.Perspective(mgl32.DegToRad(45.0), vp.Aspect(), 0.1, 10.0)
+	projectionUniform := gl.GetUniformLocation(hc.program, gl.Str("projection\x00"))
+	gl.UniformMatrix4fv(projectionUniform, 1, false, &projection[0])
+}
+
 // Update implements the update method
 func (hc *HelloCube) Update() {
-	time := glfw.GetTime()
-	elapsed := time - hc.previousTime
-	hc.previousTime = time
-
-	hc.angle += elapsed
+	hc.angle += hc.Clock.Delta()
 	hc.model = mgl32.HomogRotate3D(float32(hc.angle), mgl32.Vec3{0, 1, 0})
 }
 
 // Draw implements the draw method
-func (hc *HelloCube) Draw() {
+func (hc *HelloCube) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(hc.Color32.R, hc.Color32.G, hc.Color32.B, hc.Color32.A)
 
@@ -188,13 +183,13 @@ func (hc *HelloCube) Draw() {
 	gl.ActiveTexture(gl.TEXTURE0)
 	gl.BindTexture(gl.TEXTURE_2D, hc.texture)
 
-	gl.DrawArrays(gl.TRIANGLES, 0, 6*2*3)
+	sections.DrawArrays(gl.TRIANGLES, 0, 6*2*3)
 
 }
 
 func (hc *HelloCube) Close() {
-	gl.DeleteVertexArrays(1, &hc.vao)
-	gl.DeleteBuffers(1, &hc.vbo)
-	gl.DeleteBuffers(1, &hc.vao)
-	gl.DeleteProgram(hc.program)
+	hc.DeleteVertexArrays(hc.vao)
+	hc.DeleteBuffers(hc.vbo)
+	hc.DeleteTextures(hc.texture)
+	hc.DeletePrograms(hc.program)
 }
@@ -10,11 +10,10 @@ type HelloWindow struct {
 }
 
 func (hw *HelloWindow) InitGL() error {
-	hw.Name = "1. Hello Window"
 	return nil
 }
 
-func (hw *HelloWindow) Draw() {
+func (hw *HelloWindow) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(hw.Color32.R, hw.Color32.G, hw.Color32.B, hw.Color32.A)
 }
@@ -13,9 +13,8 @@ type HelloTriangle struct {
 }
 
 func (ht *HelloTriangle) createBuffers(vertices []float32) (uint32, uint32) {
-	var vao, vbo uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.GenBuffers(1, &vbo)
+	vao := ht.GenVertexArray()
+	vbo := ht.GenBuffer()
 
 	gl.BindVertexArray(vao)
 
@@ -33,8 +32,6 @@ func (ht *HelloTriangle) createBuffers(vertices []float32) (uint32, uint32) {
 }
 
 func (ht *HelloTriangle) InitGL() error {
-	ht.Name = "2a. Hello Triangle"
-
 	var vertexShader = `
 	#version 330 core
 	layout (location = 0) in vec3 position;
@@ -50,7 +47,7 @@ func (ht *HelloTriangle) InitGL() error {
 	}` + "\x00"
 
 	var err error
-	ht.program, err = glutils.BasicProgram(vertexShader, fragShader)
+	ht.program, err = ht.NewProgram(vertexShader, fragShader)
 	if err != nil {
 		return err
 	}
@@ -64,21 +61,21 @@ func (ht *HelloTriangle) InitGL() error {
 	return nil
 }
 
-func (ht *HelloTriangle) Draw() {
+func (ht *HelloTriangle) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
 	// Draw our first triangle
 	gl.UseProgram(ht.program)
 	gl.BindVertexArray(ht.vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	sections.DrawArrays(gl.TRIANGLES, 0, 3)
 	gl.BindVertexArray(0)
 }
 
 func (ht *HelloTriangle) Close() {
-	gl.DeleteVertexArrays(1, &ht.vao)
-	gl.DeleteBuffers(1, &ht.vbo)
-	gl.DeleteProgram(ht.program)
+	ht.DeleteVertexArrays(ht.vao)
+	ht.DeleteBuffers(ht.vbo)
+	ht.DeletePrograms(ht.program)
 }
 
 type TriangleEx1 struct {
@@ -88,7 +85,6 @@ type TriangleEx1 struct {
 }
 
 func (hs *TriangleEx1) InitGL() error {
-	hs.Name = "2b. Triangle Ex1"
 	var vertexShader = `
 	#version 330 core
 	in vec3 vert;
@@ -104,7 +100,7 @@ func (hs *TriangleEx1) InitGL() error {
 	}` + "\x00"
 
 	var err error
-	hs.program, err = glutils.BasicProgram(vertexShader, fragShader)
+	hs.program, err = hs.NewProgram(vertexShader, fragShader)
 	if err != nil {
 		return err
 	}
@@ -121,14 +117,14 @@ func (hs *TriangleEx1) InitGL() error {
 		1, 2, 3, // Second Triangle
 	}
 
-	gl.GenVertexArrays(1, &hs.vao)
+	hs.vao = hs.GenVertexArray()
 	gl.BindVertexArray(hs.vao)
 
-	gl.GenBuffers(1, &hs.vbo)
+	hs.vbo = hs.GenBuffer()
 	gl.BindBuffer(gl.ARRAY_BUFFER, hs.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*glutils.GL_FLOAT32_SIZE, gl.Ptr(vertices), gl.STATIC_DRAW)
 
-	gl.GenBuffers(1, &hs.ebo)
+	hs.ebo = hs.GenBuffer()
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, hs.ebo)
 	// seems like 4 works best here for the size of uint32
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
@@ -142,7 +138,7 @@ func (hs *TriangleEx1) InitGL() error {
 	return nil
 }
 
-func (hs *TriangleEx1) Draw() {
+func (hs *TriangleEx1) Draw(vp sections.Viewport) {
 	gl.GetIntegerv(gl.POLYGON_MODE, &hs.currentMode)
 
 	gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
@@ -151,15 +147,15 @@ func (hs *TriangleEx1) Draw() {
 
 	gl.UseProgram(hs.program)
 	gl.BindVertexArray(hs.vao)
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 	gl.BindVertexArray(0)
 	gl.PolygonMode(gl.FRONT_AND_BACK, uint32(hs.currentMode))
 }
 
 func (hs *TriangleEx1) Close() {
-	gl.DeleteVertexArrays(1, &hs.vao)
-	gl.DeleteBuffers(1, &hs.vbo)
-	gl.DeleteBuffers(1, &hs.ebo)
+	hs.DeleteVertexArrays(hs.vao)
+	hs.DeleteBuffers(hs.vbo, hs.ebo)
+	hs.DeletePrograms(hs.program)
 	gl.UseProgram(0)
 }
 
@@ -174,8 +170,6 @@ type TriangleEx2 struct {
 }
 
 func (ht *TriangleEx2) InitGL() error {
-	ht.Name = "2c. Triangle Ex2"
-
 	var vertexShader = `
 	#version 330 core
 	layout (location = 0) in vec3 position;
@@ -205,12 +199,12 @@ func (ht *TriangleEx2) InitGL() error {
 	}` + "\x00"
 
 	var err error
-	ht.program, err = glutils.BasicProgram(vertexShader, fragShader)
+	ht.program, err = ht.NewProgram(vertexShader, fragShader)
 	if err != nil {
 		return err
 	}
 
-	ht.program2, err = glutils.BasicProgram(vertexShader2, fragShader2)
+	ht.program2, err = ht.NewProgram(vertexShader2, fragShader2)
 	if err != nil {
 		return err
 	}
@@ -233,18 +227,25 @@ func (ht *TriangleEx2) InitGL() error {
 	return nil
 }
 
-func (ht *TriangleEx2) Draw() {
+func (ht *TriangleEx2) Close() {
+	ht.HelloTriangle.Close()
+	ht.DeleteVertexArrays(ht.vao2)
+	ht.DeleteBuffers(ht.vbo2)
+	ht.DeletePrograms(ht.program2)
+}
+
+func (ht *TriangleEx2) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
 	// Draw our first triangle
 	gl.UseProgram(ht.program)
 	gl.BindVertexArray(ht.vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	sections.DrawArrays(gl.TRIANGLES, 0, 3)
 	gl.BindVertexArray(0)
 
 	gl.UseProgram(ht.program2)
 	gl.BindVertexArray(ht.vao2)
-	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	sections.DrawArrays(gl.TRIANGLES, 0, 3)
 	gl.BindVertexArray(0)
 }
@@ -2,7 +2,6 @@ package getstarted
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
 	"math"
@@ -15,13 +14,7 @@ type HelloShaders struct {
 }
 
 func (hs *HelloShaders) createShader(v, f string) error {
-	var err error
-	hs.shader, err = glutils.NewShader(v, f, "")
-
-	if err != nil {
-		return err
-	}
-	return nil
+	return hs.LoadShader(&hs.shader, v, f, "")
 }
 
 func (hs *HelloShaders) createBuffers() {
@@ -43,15 +36,13 @@ func (hs *HelloShaders) createBuffers() {
 		Attributes: attr,
 	}
 
-	hs.va.Setup()
+	hs.SetupVertexArray(&hs.va)
 }
 
 func (hs *HelloShaders) InitGL() error {
-	hs.Name = "3a. Shaders"
-
 	if err := hs.createShader(
-		"_assets/getting_started/3.shaders/basic.vs",
-		"_assets/getting_started/3.shaders/basic.frag"); err != nil {
+		hs.Asset("getting_started/3.shaders/basic.vs"),
+		hs.Asset("getting_started/3.shaders/basic.frag")); err != nil {
 		return err
 	}
 
@@ -60,20 +51,20 @@ func (hs *HelloShaders) InitGL() error {
 	return nil
 }
 
-func (hs *HelloShaders) Draw() {
+func (hs *HelloShaders) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(hs.Color32.R, hs.Color32.G, hs.Color32.B, hs.Color32.A)
 
 	// Draw the triangle
 	gl.UseProgram(hs.shader.Program)
 	gl.BindVertexArray(hs.va.Vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	sections.DrawArrays(gl.TRIANGLES, 0, 3)
 	gl.BindVertexArray(0)
 }
 
 func (hs *HelloShaders) Close() {
-	hs.shader.Delete()
-	hs.va.Delete()
+	hs.DeleteShader(&hs.shader)
+	hs.DeleteVertexArray(&hs.va)
 }
 
 type ShaderEx1 struct {
@@ -84,10 +75,9 @@ type ShaderEx1 struct {
 }
 
 func (hs *ShaderEx1) InitGL() error {
-	hs.Name = "3b. Shaders Ex1"
 	if err := hs.createShader(
-		"_assets/getting_started/3.shaders/basic.vs",
-		"_assets/getting_started/3.shaders/uniform.frag"); err != nil {
+		hs.Asset("getting_started/3.shaders/basic.vs"),
+		hs.Asset("getting_started/3.shaders/uniform.frag")); err != nil {
 		return err
 	}
 
@@ -96,11 +86,11 @@ func (hs *ShaderEx1) InitGL() error {
 }
 
 func (hs *ShaderEx1) Update() {
-	hs.timeValue = glfw.GetTime()
+	hs.timeValue = hs.Clock.Time()
 	hs.greenValue = float32(math.Sin(hs.timeValue)/2) + 0.5
 }
 
-func (hs *ShaderEx1) Draw() {
+func (hs *ShaderEx1) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(hs.Color32.R, hs.Color32.G, hs.Color32.B, hs.Color32.A)
 
@@ -108,7 +98,7 @@ func (hs *ShaderEx1) Draw() {
 	gl.UseProgram(hs.shader.Program)
 	gl.Uniform4f(hs.shader.Uniforms["ourColor"], 0.0, hs.greenValue, 0.0, 1.0)
 	gl.BindVertexArray(hs.va.Vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	sections.DrawArrays(gl.TRIANGLES, 0, 3)
 	gl.BindVertexArray(0)
 }
 
@@ -121,10 +111,9 @@ type ShaderEx2 struct {
 }
 
 func (hs *ShaderEx2) InitGL() error {
-	hs.Name = "3c. Shaders Ex2"
 	if err := hs.createShader(
-		"_assets/getting_started/3.shaders/reverse.vs",
-		"_assets/getting_started/3.shaders/basic.frag"); err != nil {
+		hs.Asset("getting_started/3.shaders/reverse.vs"),
+		hs.Asset("getting_started/3.shaders/basic.frag")); err != nil {
 		return err
 	}
 
@@ -138,10 +127,9 @@ type ShaderEx3 struct {
 }
 
 func (hs *ShaderEx3) InitGL() error {
-	hs.Name = "3b. Shaders Ex3"
 	if err := hs.createShader(
-		"_assets/getting_started/3.shaders/offset.vs",
-		"_assets/getting_started/3.shaders/basic.frag"); err != nil {
+		hs.Asset("getting_started/3.shaders/offset.vs"),
+		hs.Asset("getting_started/3.shaders/basic.frag")); err != nil {
 		return err
 	}
 
@@ -158,10 +146,9 @@ type ShaderEx4 struct {
 }
 
 func (hs *ShaderEx4) InitGL() error {
-	hs.Name = "3c. Shaders Ex4"
 	if err := hs.createShader(
-		"_assets/getting_started/3.shaders/ex4.vs",
-		"_assets/getting_started/3.shaders/ex4.frag"); err != nil {
+		hs.Asset("getting_started/3.shaders/ex4.vs"),
+		hs.Asset("getting_started/3.shaders/ex4.frag")); err != nil {
 		return err
 	}
 
@@ -188,5 +175,5 @@ func (hs *ShaderEx4) createBuffers() {
 		Attributes: attr,
 	}
 
-	hs.va.Setup()
-}
\ No newline at end of file
+	hs.SetupVertexArray(&hs.va)
+}
@@ -1,6 +1,8 @@
 package getstarted
 
 import (
+	"encoding/json"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/raedatoui/glutils"
@@ -15,8 +17,8 @@ type HelloTextures struct {
 }
 
 func (ht *HelloTextures) getShaders() []string {
-	return []string{"_assets/getting_started/4.textures/texture.vs",
-		"_assets/getting_started/4.textures/texture.frag"}
+	return []string{ht.Asset("getting_started/4.textures/texture.vs"),
+		ht.Asset("getting_started/4.textures/texture.frag")}
 }
 func (ht *HelloTextures) getVertices() []float32 {
 	return []float32{
@@ -45,15 +47,11 @@ func (ht *HelloTextures) createBuffers(vertices []float32) {
 		DrawMode:   gl.STATIC_DRAW,
 		Attributes: attr,
 	}
-	ht.va.Setup()
+	ht.SetupVertexArray(&ht.va)
 }
 func (ht *HelloTextures) InitGL() error {
-	ht.Name = "4. Textures"
-
-	var err error
 	shaders := ht.getShaders()
-	ht.shader, err = glutils.NewShader(shaders[0], shaders[1], "")
-	if err != nil {
+	if err := ht.LoadShader(&ht.shader, shaders[0], shaders[1], ""); err != nil {
 		return err
 	}
 
@@ -62,7 +60,7 @@ func (ht *HelloTextures) InitGL() error {
 	// ====================
 	// Texture 1
 	// ====================
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/container.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, ht.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		ht.texture1 = tex
@@ -71,7 +69,7 @@ func (ht *HelloTextures) InitGL() error {
 	// ====================
 	// Texture 2
 	// ====================
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, ht.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		ht.texture2 = tex
@@ -80,7 +78,7 @@ func (ht *HelloTextures) InitGL() error {
 	return nil
 }
 
-func (ht *HelloTextures) Draw() {
+func (ht *HelloTextures) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
@@ -98,13 +96,14 @@ func (ht *HelloTextures) Draw() {
 
 	// Draw container
 	gl.BindVertexArray(ht.va.Vao)
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 	gl.BindVertexArray(0)
 }
 
 func (ht *HelloTextures) Close() {
-	ht.shader.Delete()
-	ht.va.Delete()
+	ht.DeleteShader(&ht.shader)
+	ht.DeleteVertexArray(&ht.va)
+	ht.DeleteTextures(ht.texture1, ht.texture2)
 }
 
 type TexturesEx1 struct {
@@ -112,16 +111,12 @@ type TexturesEx1 struct {
 }
 
 func (ht *TexturesEx1) getShaders() []string {
-	return []string{"_assets/getting_started/4.textures/texture.vs",
-		"_assets/getting_started/4.textures/textureex1.frag"}
+	return []string{ht.Asset("getting_started/4.textures/texture.vs"),
+		ht.Asset("getting_started/4.textures/textureex1.frag")}
 }
 func (ht *TexturesEx1) InitGL() error {
-	ht.Name = "4a. Textures Ex1"
-
-	var err error
 	shaders := ht.getShaders()
-	ht.shader, err = glutils.NewShader(shaders[0], shaders[1], "")
-	if err != nil {
+	if err := ht.LoadShader(&ht.shader, shaders[0], shaders[1], ""); err != nil {
 		return err
 	}
 
@@ -130,7 +125,7 @@ func (ht *TexturesEx1) InitGL() error {
 	// ====================
 	// Texture 1
 	// ====================
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/container.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, ht.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		ht.texture1 = tex
@@ -139,7 +134,7 @@ func (ht *TexturesEx1) InitGL() error {
 	// ====================
 	// Texture 2
 	// ====================
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, ht.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		ht.texture2 = tex
@@ -166,12 +161,8 @@ func (ht *TexturesEx2) getVertices() []float32 {
 	}
 }
 func (ht *TexturesEx2) InitGL() error {
-	ht.Name = "4b. Textures Ex2"
-
-	var err error
 	shaders := ht.getShaders()
-	ht.shader, err = glutils.NewShader(shaders[0], shaders[1], "")
-	if err != nil {
+	if err := ht.LoadShader(&ht.shader, shaders[0], shaders[1], ""); err != nil {
 		return err
 	}
 
@@ -180,7 +171,7 @@ func (ht *TexturesEx2) InitGL() error {
 	// ====================
 	// Texture 1
 	// ====================
-	if tex, err := glutils.NewTexture(gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, gl.NEAREST, gl.NEAREST, "_assets/images/container.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, gl.NEAREST, gl.NEAREST, ht.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		ht.texture1 = tex
@@ -189,7 +180,7 @@ func (ht *TexturesEx2) InitGL() error {
 	// ====================
 	// Texture 2
 	// ====================
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, ht.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		ht.texture2 = tex
@@ -216,25 +207,21 @@ func (ht *TexturesEx3) getVertices() []float32 {
 	}
 }
 func (ht *TexturesEx3) InitGL() error {
-	ht.Name = "4c. Textures Ex3"
-
-	var err error
 	shaders := ht.getShaders()
-	ht.shader, err = glutils.NewShader(shaders[0], shaders[1], "")
-	if err != nil {
+	if err := ht.LoadShader(&ht.shader, shaders[0], shaders[1], ""); err != nil {
 		return err
 	}
 	ht.createBuffers(ht.getVertices())
 
 	// Texture 1
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, "_assets/images/container.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, ht.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		ht.texture1 = tex
 	}
 
 	// Texture 2
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, ht.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		ht.texture2 = tex
@@ -254,29 +241,25 @@ func (ht *TexturesEx3) GetSubHeader() string {
 }
 
 func (ht *TexturesEx4) getShaders() []string {
-	return []string{"_assets/getting_started/4.textures/texture.vs",
-		"_assets/getting_started/4.textures/textureex4.frag"}
+	return []string{ht.Asset("getting_started/4.textures/texture.vs"),
+		ht.Asset("getting_started/4.textures/textureex4.frag")}
 }
 func (ht *TexturesEx4) InitGL() error {
-	ht.Name = "4d. Textures Ex4"
-
-	var err error
 	shaders := ht.getShaders()
-	ht.shader, err = glutils.NewShader(shaders[0], shaders[1], "")
-	if err != nil {
+	if err := ht.LoadShader(&ht.shader, shaders[0], shaders[1], ""); err != nil {
 		return err
 	}
 	ht.createBuffers(ht.getVertices())
 
 	// Texture 1
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, "_assets/images/container.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, ht.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		ht.texture1 = tex
 	}
 
 	// Texture 2
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.NEAREST, gl.NEAREST, ht.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		ht.texture2 = tex
@@ -300,7 +283,20 @@ func (ht *TexturesEx4) HandleKeyboard(k glfw.Key, s int, a glfw.Action, mk glfw.
 	}
 }
 
-func (ht *TexturesEx4) Draw() {
+// SaveState keeps the mix value between runs
+func (ht *TexturesEx4) SaveState() (json.RawMessage, error) {
+	return json.Marshal(ht.mixValue)
+}
+
+func (ht *TexturesEx4) LoadState(data json.RawMessage) error {
+	return json.Unmarshal(data, &ht.mixValue)
+}
+
+func (ht *TexturesEx4) ResetState() {
+	ht.mixValue = 0
+}
+
+func (ht *TexturesEx4) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
@@ -320,7 +316,7 @@ func (ht *TexturesEx4) Draw() {
 
 	// Draw container
 	gl.BindVertexArray(ht.va.Vao)
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 	gl.BindVertexArray(0)
 }
 
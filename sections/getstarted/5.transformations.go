@@ -4,7 +4,6 @@ import (
 	"math"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
@@ -19,19 +18,13 @@ type HelloTransformations struct {
 	rotationAxis       mgl32.Vec3
 }
 
-func (ht *HelloTransformations) GetHeader() string {
-	return "5. Transformations"
-}
-
 func (ht *HelloTransformations) InitGL() error {
 	ht.translationMat = mgl32.Translate3D(0.5, -0.5, 0.0)
 	ht.rotationAxis = mgl32.Vec3{0.0, 0.0, 1.0}.Normalize()
 
-	var err error
-	ht.shader, err = glutils.NewShader(
-		"_assets/getting_started/5.transformations/transform.vs",
-		"_assets/getting_started/5.transformations/transform.frag", "")
-	if err != nil {
+	if err := ht.LoadShader(&ht.shader,
+		ht.Asset("getting_started/5.transformations/transform.vs"),
+		ht.Asset("getting_started/5.transformations/transform.frag"), ""); err != nil {
 		return err
 	}
 
@@ -59,17 +52,17 @@ func (ht *HelloTransformations) InitGL() error {
 		Stride:     8,
 		Attributes: attr,
 	}
-	ht.va.Setup()
+	ht.SetupVertexArray(&ht.va)
 
 	// Texture 1
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/container.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, ht.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		ht.texture1 = tex
 	}
 
 	// Texture 2
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := ht.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, ht.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		ht.texture2 = tex
@@ -80,11 +73,11 @@ func (ht *HelloTransformations) InitGL() error {
 
 func (ht *HelloTransformations) getTransform() mgl32.Mat4 {
 	// rotate
-	transform := ht.translationMat.Mul4(mgl32.HomogRotate3D(float32(glfw.GetTime()), ht.rotationAxis))
+	transform := ht.translationMat.Mul4(mgl32.HomogRotate3D(float32(ht.Clock.Time()), ht.rotationAxis))
 	return transform
 }
 
-func (ht *HelloTransformations) Draw() {
+func (ht *HelloTransformations) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
@@ -107,34 +100,31 @@ func (ht *HelloTransformations) Draw() {
 
 	// Draw container
 	gl.BindVertexArray(ht.va.Vao)
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 	gl.BindVertexArray(0)
 }
 
 func (ht *HelloTransformations) Close() {
-	ht.shader.Delete()
-	ht.va.Delete()
+	ht.DeleteShader(&ht.shader)
+	ht.DeleteVertexArray(&ht.va)
+	ht.DeleteTextures(ht.texture1, ht.texture2)
 }
 
 type TransformationEx1 struct {
 	HelloTransformations
 }
 
-func (ht *TransformationEx1) GetHeader() string {
-	return "5a. Transformations Ex1"
-}
-
 func (ht *TransformationEx1) GetSubHeader() string {
 	return "Rotate then translate"
 }
 
 func (ht *TransformationEx1) getTransform() mgl32.Mat4 {
 	// rotate
-	transform := mgl32.HomogRotate3D(float32(glfw.GetTime()), ht.rotationAxis).Mul4(ht.translationMat)
+	transform := mgl32.HomogRotate3D(float32(ht.Clock.Time()), ht.rotationAxis).Mul4(ht.translationMat)
 	return transform
 }
 
-func (ht *TransformationEx1) Draw() {
+func (ht *TransformationEx1) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
@@ -157,7 +147,7 @@ func (ht *TransformationEx1) Draw() {
 
 	// Draw container
 	gl.BindVertexArray(ht.va.Vao)
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 	gl.BindVertexArray(0)
 }
 
@@ -165,7 +155,7 @@ type TransformationEx2 struct {
 	HelloTransformations
 }
 
-func (ht *TransformationEx2) Draw() {
+func (ht *TransformationEx2) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ht.Color32.R, ht.Color32.G, ht.Color32.B, ht.Color32.A)
 
@@ -183,16 +173,16 @@ func (ht *TransformationEx2) Draw() {
 
 	// Draw container
 	gl.BindVertexArray(ht.va.Vao)
-	transform := ht.translationMat.Mul4(mgl32.HomogRotate3D(float32(glfw.GetTime()), ht.rotationAxis))
+	transform := ht.translationMat.Mul4(mgl32.HomogRotate3D(float32(ht.Clock.Time()), ht.rotationAxis))
 	// here we create a pointer from the first element of the matrix?
 	// read up and update this comm
 	gl.UniformMatrix4fv(ht.shader.Uniforms["transform"], 1, false, &transform[0])
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 
-	scaleAmount := float32(math.Sin(glfw.GetTime()))
+	scaleAmount := float32(math.Sin(ht.Clock.Time()))
 	transform = mgl32.Translate3D(-0.5, 0.5, 0.0).Mul4(mgl32.Scale3D(scaleAmount, scaleAmount, scaleAmount))
 	gl.UniformMatrix4fv(ht.shader.Uniforms["transform"], 1, false, &transform[0])
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	sections.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
 
 	gl.BindVertexArray(0)
 }
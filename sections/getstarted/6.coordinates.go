@@ -2,7 +2,6 @@ package getstarted
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
@@ -18,19 +17,10 @@ type HelloCoordinates struct {
 	rotationAxis       mgl32.Vec3
 }
 
-func (hc *HelloCoordinates) GetHeader() string {
-	return "6. Coordinate Systems"
-}
-
 func (hc *HelloCoordinates) createShader() error {
-	var err error
-	hc.shader, err = glutils.NewShader(
-		"_assets/getting_started/6.coordinates/coordinate.vs",
-		"_assets/getting_started/6.coordinates/coordinate.frag", "")
-	if err != nil {
-		return err
-	}
-	return nil
+	return hc.LoadShader(&hc.shader,
+		hc.Asset("getting_started/6.coordinates/coordinate.vs"),
+		hc.Asset("getting_started/6.coordinates/coordinate.frag"), "")
 }
 func (hc *HelloCoordinates) createBuffers() error {
 	vertices := []float32{
@@ -88,7 +78,7 @@ func (hc *HelloCoordinates) createBuffers() error {
 		Normalized: false,
 		Attributes: attr,
 	}
-	hc.va.Setup()
+	hc.SetupVertexArray(&hc.va)
 
 	hc.rotationAxis = mgl32.Vec3{1.0, 0.3, 0.5}.Normalize()
 
@@ -108,14 +98,14 @@ func (hc *HelloCoordinates) createBuffers() error {
 }
 func (hc *HelloCoordinates) createTextures() error {
 	// Texture 1
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/container.png"); err != nil {
+	if tex, err := hc.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, hc.Asset("images/container.png")); err != nil {
 		return err
 	} else {
 		hc.texture1 = tex
 	}
 
 	// Texture 2
-	if tex, err := glutils.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, "_assets/images/awesomeface.png"); err != nil {
+	if tex, err := hc.NewTexture(gl.REPEAT, gl.REPEAT, gl.LINEAR, gl.LINEAR, hc.Asset("images/awesomeface.png")); err != nil {
 		return err
 	} else {
 		hc.texture2 = tex
@@ -149,10 +139,10 @@ func (hc *HelloCoordinates) setTextures() {
 	gl.BindTexture(gl.TEXTURE_2D, hc.texture2)
 	gl.Uniform1i(hc.shader.Uniforms["ourTexture2"], 1)
 }
-func (hc *HelloCoordinates) setTransformations() {
+func (hc *HelloCoordinates) setTransformations(vp sections.Viewport) {
 	// Create transformations
 	view := mgl32.Translate3D(0.0, 0.0, -3.0)
-	projection := mgl32.Perspective(45.0, sections.Ratio, 0.1, 100.0)
+	projection := mgl32.Perspective(45.0, vp.Aspect(), 0.1, 100.0)
 	// Pass the matrices to the shader
 	gl.UniformMatrix4fv(hc.shader.Uniforms["view"], 1, false, &view[0])
 	// Note: currently we set the projection matrix each frame,
@@ -166,24 +156,25 @@ func (hc *HelloCoordinates) renderVertexArray() {
 		// Calculate the model matrix for each object and pass it to shader before drawing
 		model := hc.cubePositions[i]
 
-		angle := float32(glfw.GetTime()) * float32(i+1)
+		angle := float32(hc.Clock.Time()) * float32(i+1)
 
 		model = model.Mul4(mgl32.HomogRotate3D(angle, hc.rotationAxis))
 		gl.UniformMatrix4fv(hc.shader.Uniforms["model"], 1, false, &model[0])
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+		sections.DrawArrays(gl.TRIANGLES, 0, 36)
 	}
 	gl.BindVertexArray(0)
 }
-func (hc *HelloCoordinates) Draw() {
+func (hc *HelloCoordinates) Draw(vp sections.Viewport) {
 	hc.clear()
 	// Activate shader
 	gl.UseProgram(hc.shader.Program)
 	hc.setTextures()
-	hc.setTransformations()
+	hc.setTransformations(vp)
 	hc.renderVertexArray()
 }
 
 func (hc *HelloCoordinates) Close() {
-	hc.shader.Delete()
-	hc.va.Delete()
+	hc.DeleteShader(&hc.shader)
+	hc.DeleteVertexArray(&hc.va)
+	hc.DeleteTextures(hc.texture1, hc.texture2)
 }
@@ -2,7 +2,6 @@ package getstarted
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
@@ -10,26 +9,17 @@ import (
 
 type HelloCamera struct {
 	HelloCoordinates
-	camera               glutils.Camera
-	deltaTime, lastFrame float64
-	w, a, s, d           bool
-	lastX, lastY         float64
-	firstMouse           bool
-}
-
-func (hc *HelloCamera) GetHeader() string {
-	return "7. Camera (use WSDA and mouse)"
+	sections.CameraController
 }
 
 func (hc *HelloCamera) InitGL() error {
-	hc.camera = glutils.NewCamera(
+	hc.CameraController = sections.NewCameraController(glutils.NewCamera(
 		mgl32.Vec3{0.0, 0.0, 3.0},
 		mgl32.Vec3{0.0, 1.0, 3.0},
 		glutils.YAW, glutils.PITCH,
-	)
-	hc.lastX = sections.WIDTH / 2
-	hc.lastY = sections.HEIGHT / 2
-	hc.firstMouse = true
+	))
+	// the box around the cube positions of HelloCoordinates, with room for the rotations
+	hc.SetBounds(mgl32.Vec3{-4.7, -3.1, -15.9}, mgl32.Vec3{3.3, 5.9, 0.9})
 
 	if err := hc.createShader(); err != nil {
 		return err
@@ -44,29 +34,18 @@ func (hc *HelloCamera) InitGL() error {
 	return nil
 }
 
+func (hc *HelloCamera) GetSubHeader() string {
+	return hc.Help()
+}
+
 func (hc *HelloCamera) Update() {
-	// Set frame time
-	currentFrame := glfw.GetTime()
-	hc.deltaTime = currentFrame - hc.lastFrame
-	hc.lastFrame = currentFrame
-	if hc.w {
-		hc.camera.ProcessKeyboard(glutils.FORWARD, hc.deltaTime)
-	}
-	if hc.s {
-		hc.camera.ProcessKeyboard(glutils.BACKWARD, hc.deltaTime)
-	}
-	if hc.a {
-		hc.camera.ProcessKeyboard(glutils.LEFT, hc.deltaTime)
-	}
-	if hc.d {
-		hc.camera.ProcessKeyboard(glutils.RIGHT, hc.deltaTime)
-	}
+	hc.UpdateCamera(hc.Clock.FrameDelta())
 }
 
-func (hc *HelloCamera) setTransformations() {
+func (hc *HelloCamera) setTransformations(vp sections.Viewport) {
 	// Create transformations
-	view := hc.camera.GetViewMatrix()
-	projection := mgl32.Perspective(float32(hc.camera.Zoom), sections.Ratio, 0.1, 1000.0)
+	view := hc.Camera.GetViewMatrix()
+	projection := hc.Projection(0.1, 1000.0)
 
 	// Pass the matrices to the shader
 	gl.UniformMatrix4fv(hc.shader.Uniforms["view"], 1, false, &view[0])
@@ -75,38 +54,11 @@ func (hc *HelloCamera) setTransformations() {
 	gl.UniformMatrix4fv(hc.shader.Uniforms["projection"], 1, false, &projection[0])
 }
 
-func (hc *HelloCamera) Draw() {
+func (hc *HelloCamera) Draw(vp sections.Viewport) {
 	hc.clear()
 	// Activate shader
 	gl.UseProgram(hc.shader.Program)
 	hc.setTextures()
-	hc.setTransformations()
+	hc.setTransformations(vp)
 	hc.renderVertexArray()
 }
-
-func (hc *HelloCamera) HandleKeyboard(k glfw.Key, s int, a glfw.Action, mk glfw.ModifierKey, keys map[glfw.Key]bool) {
-	hc.w = keys[glfw.KeyW]
-	hc.a = keys[glfw.KeyA]
-	hc.s = keys[glfw.KeyS]
-	hc.d = keys[glfw.KeyD]
-}
-
-func (hc *HelloCamera) HandleMousePosition(xpos, ypos float64) {
-	if hc.firstMouse {
-		hc.lastX = xpos
-		hc.lastY = ypos
-		hc.firstMouse = false
-	}
-
-	xoffset := xpos - hc.lastX
-	yoffset := hc.lastY - ypos // Reversed since y-coordinates go from bottom to left
-
-	hc.lastX = xpos
-	hc.lastY = ypos
-
-	hc.camera.ProcessMouseMovement(xoffset, yoffset, true)
-}
-
-func (hc *HelloCamera) HandleScroll(xoff, yoff float64) {
-	hc.camera.ProcessMouseScroll(yoff)
-}
@@ -0,0 +1,61 @@
+package getstarted
+
+import "github.com/raedatoui/learn-opengl-golang/sections"
+
+func init() {
+	sections.Register(sections.Entry{
+		Section: sections.Installation, Order: 0,
+		Name:        "0. Test Cube From github.com/go-gl/examples",
+		Description: "textured spinning cube from the go-gl examples",
+		New:         func() sections.Slide { return new(HelloCube) },
+	})
+
+	for _, e := range []sections.Entry{
+		{Order: 10, Name: "1. Hello Window", Description: "clear the window with a color",
+			New: func() sections.Slide { return new(HelloWindow) }},
+
+		{Order: 20, Name: "2a. Hello Triangle", Description: "first vertex buffer and shader program",
+			New: func() sections.Slide { return new(HelloTriangle) }},
+		{Order: 21, Name: "2b. Triangle Ex1", Description: "indexed drawing with an element buffer",
+			New: func() sections.Slide { return new(TriangleEx1) }},
+		{Order: 22, Name: "2c. Triangle Ex2", Description: "two triangles with two programs",
+			New: func() sections.Slide { return new(TriangleEx2) }},
+
+		{Order: 30, Name: "3a. Shaders", Description: "per vertex colors",
+			New: func() sections.Slide { return new(HelloShaders) }},
+		{Order: 31, Name: "3b. Shaders Ex1", Description: "color animated with a uniform",
+			New: func() sections.Slide { return new(ShaderEx1) }},
+		{Order: 32, Name: "3c. Shaders Ex2", Description: "upside down triangle",
+			New: func() sections.Slide { return new(ShaderEx2) }},
+		{Order: 33, Name: "3d. Shaders Ex3", Description: "triangle offset with a uniform",
+			New: func() sections.Slide { return new(ShaderEx3) }},
+		{Order: 34, Name: "3e. Shaders Ex4", Description: "vertex position as the color",
+			New: func() sections.Slide { return new(ShaderEx4) }},
+
+		{Order: 40, Name: "4. Textures", Description: "two textures mixed in the frag shader",
+			New: func() sections.Slide { return new(HelloTextures) }},
+		{Order: 41, Name: "4a. Textures Ex1", Description: "flipped texture",
+			New: func() sections.Slide { return new(TexturesEx1) }},
+		{Order: 42, Name: "4b. Textures Ex2", Description: "wrapping and nearest filtering",
+			New: func() sections.Slide { return new(TexturesEx2) }},
+		{Order: 43, Name: "4c. Textures Ex3", Description: "scaled down texture coordinates",
+			New: func() sections.Slide { return new(TexturesEx3) }},
+		{Order: 44, Name: "4d. Textures Ex4", Description: "cross fade controlled with the arrows",
+			New: func() sections.Slide { return new(TexturesEx4) }},
+
+		{Order: 50, Name: "5. Transformations", Description: "rotating and translating a quad",
+			New: func() sections.Slide { return new(HelloTransformations) }},
+		{Order: 51, Name: "5a. Transformations Ex1", Description: "rotate then translate",
+			New: func() sections.Slide { return new(TransformationEx1) }},
+		{Order: 52, Name: "5b. Transformations Ex2", Description: "second container scaled over time",
+			New: func() sections.Slide { return new(TransformationEx2) }},
+
+		{Order: 60, Name: "6. Coordinate Systems", Description: "model, view and projection matrices",
+			New: func() sections.Slide { return new(HelloCoordinates) }},
+		{Order: 70, Name: "7. Camera", Description: "fly through camera",
+			New: func() sections.Slide { return new(HelloCamera) }},
+	} {
+		e.Section = sections.GettingStarted
+		sections.Register(e)
+	}
+}
@@ -0,0 +1,162 @@
+package sections
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// GLError is a GL error or a debug message, attributed to the slide and the phase it happened in
+type GLError struct {
+	Slide   string
+	Phase   string
+	Message string
+	// Count is the number of times it happened, errors in Draw repeat every frame
+	Count int
+}
+
+func (e GLError) String() string {
+	s := fmt.Sprintf("%s: %s: %s", e.Slide, e.Phase, e.Message)
+	if e.Count > 1 {
+		s += fmt.Sprintf(" (x%d)", e.Count)
+	}
+	return s
+}
+
+// GLReport collects the GL errors of every slide. The main loop calls Begin before each
+// phase of a slide, like InitGL, Update, Draw and Close, and End after it. Errors raised
+// outside of a phase are attributed to "main".
+type GLReport struct {
+	errors       []GLError
+	index        map[GLError]int
+	slide, phase string
+	// debugOutput is set once the KHR_debug callback reports the errors
+	debugOutput bool
+}
+
+func NewGLReport() *GLReport {
+	return &GLReport{index: make(map[GLError]int)}
+}
+
+// Begin checks for pending errors and starts attributing new ones to the slide and phase
+func (r *GLReport) Begin(slide, phase string) {
+	r.Check()
+	r.slide, r.phase = slide, phase
+}
+
+// End checks for the errors raised during the phase
+func (r *GLReport) End() {
+	r.Check()
+	r.slide, r.phase = "", ""
+}
+
+// Check drains the GL error flags
+func (r *GLReport) Check() {
+	// GL can hold several error flags at once, GetError returns and clears one at a time
+	for i := 0; i < 16; i++ {
+		e := gl.GetError()
+		if e == gl.NO_ERROR {
+			return
+		}
+		r.flag(e)
+	}
+}
+
+// flag reports an error flag, unless the debug callback already reported the error with
+// more details. The flag is still cleared so it isnt seen by the next phase.
+func (r *GLReport) flag(e uint32) {
+	if !r.debugOutput {
+		r.add(glErrorName(e))
+	}
+}
+
+func (r *GLReport) add(msg string) {
+	e := GLError{Slide: r.slide, Phase: r.phase, Message: msg}
+	if e.Slide == "" {
+		e.Slide = "main"
+	}
+	if i, ok := r.index[e]; ok {
+		r.errors[i].Count++
+		return
+	}
+	r.index[e] = len(r.errors)
+	e.Count = 1
+	r.errors = append(r.errors, e)
+}
+
+// Errors returns the errors of a slide, or of all the slides when name is empty
+func (r *GLReport) Errors(slide string) []GLError {
+	var errs []GLError
+	for _, e := range r.errors {
+		if slide == "" || e.Slide == slide {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// String lists the errors grouped by slide
+func (r *GLReport) String() string {
+	errs := r.Errors("")
+	if len(errs) == 0 {
+		return "no GL errors"
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Slide < errs[j].Slide
+	})
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = e.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// InstallDebugCallback routes the KHR_debug messages into the report, synchronously so they
+// are attributed to the phase that caused them, and Check stops reporting the error flags
+// the callback already saw. It returns false when the driver doesnt support it, the errors
+// are still caught by Check.
+func (r *GLReport) InstallDebugCallback() bool {
+	if !hasExtension("GL_KHR_debug") {
+		return false
+	}
+	gl.Enable(gl.DEBUG_OUTPUT)
+	gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+	gl.DebugMessageCallback(func(source, gltype, id, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+		if severity == gl.DEBUG_SEVERITY_NOTIFICATION {
+			return
+		}
+		r.add(strings.TrimSpace(message))
+	}, nil)
+	r.debugOutput = true
+	return true
+}
+
+func hasExtension(name string) bool {
+	var n int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &n)
+	for i := uint32(0); i < uint32(n); i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i)) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func glErrorName(e uint32) string {
+	switch e {
+	case gl.INVALID_ENUM:
+		return "GL_INVALID_ENUM"
+	case gl.INVALID_VALUE:
+		return "GL_INVALID_VALUE"
+	case gl.INVALID_OPERATION:
+		return "GL_INVALID_OPERATION"
+	case gl.INVALID_FRAMEBUFFER_OPERATION:
+		return "GL_INVALID_FRAMEBUFFER_OPERATION"
+	case gl.OUT_OF_MEMORY:
+		return "GL_OUT_OF_MEMORY"
+	}
+	return fmt.Sprintf("GL error 0x%x", e)
+}
@@ -0,0 +1,50 @@
+package sections
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestGLReport(t *testing.T) {
+	r := NewGLReport()
+	r.slide, r.phase = "a", "Draw"
+	r.flag(gl.INVALID_ENUM)
+	r.flag(gl.INVALID_ENUM)
+	r.phase = "Close"
+	r.flag(gl.INVALID_VALUE)
+	r.slide, r.phase = "", ""
+	r.flag(0x1234)
+
+	want := []GLError{
+		{Slide: "a", Phase: "Draw", Message: "GL_INVALID_ENUM", Count: 2},
+		{Slide: "a", Phase: "Close", Message: "GL_INVALID_VALUE", Count: 1},
+	}
+	if got := r.Errors("a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("errors of a %v, want %v", got, want)
+	}
+	if got := len(r.Errors("")); got != 3 {
+		t.Errorf("%d errors, want 3", got)
+	}
+	if got, want := r.String(), "a: Draw: GL_INVALID_ENUM (x2)\na: Close: GL_INVALID_VALUE\nmain: : GL error 0x1234"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := NewGLReport().String(); got != "no GL errors" {
+		t.Errorf("empty report %q", got)
+	}
+}
+
+func TestGLReportDebugOutput(t *testing.T) {
+	r := NewGLReport()
+	r.debugOutput = true
+	r.slide, r.phase = "a", "Draw"
+	// the callback reports the error, then Check finds its flag
+	r.add("GL_INVALID_ENUM error generated. Invalid enum.")
+	r.flag(gl.INVALID_ENUM)
+
+	want := []GLError{{Slide: "a", Phase: "Draw", Message: "GL_INVALID_ENUM error generated. Invalid enum.", Count: 1}}
+	if got := r.Errors(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("errors %v, want %v", got, want)
+	}
+}
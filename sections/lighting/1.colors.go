@@ -2,7 +2,6 @@ package lighting
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
@@ -10,56 +9,36 @@ import (
 
 type LightingColors struct {
 	sections.BaseSketch
+	sections.CameraController
 	lightingShader, lampShader glutils.Shader
 	containerVa, lightVa       glutils.VertexArray
-	lastX                      float64
-	lastY                      float64
-	firstMouse                 bool
-	deltaTime, lastFrame       float64
-	camera                     glutils.Camera
 	lightPos                   mgl32.Vec3
-	w, a, s, d                 bool
 	rotationAxis               mgl32.Vec3
 	translationMat             mgl32.Mat4
 	lightPositionMat           mgl32.Mat4
 	scaleMat                   mgl32.Mat4
 }
 
-func (lc *LightingColors) GetHeader() string {
-	return "1. Colors"
-}
-
 func (lc *LightingColors) initShaders(v1, f1, v2, f2 string) error {
-	if sh, err := glutils.NewShader(v1, f1, ""); err != nil {
-		return err
-	} else {
-		lc.lightingShader = sh
-	}
-	if sh, err := glutils.NewShader(v2, f2, ""); err != nil {
+	if err := lc.LoadShader(&lc.lightingShader, v1, f1, ""); err != nil {
 		return err
-	} else {
-		lc.lampShader = sh
 	}
-	return nil
+	return lc.LoadShader(&lc.lampShader, v2, f2, "")
 }
 func (lc *LightingColors) initCamera() {
 	// Camera
-	lc.camera = glutils.NewCamera(
+	lc.CameraController = sections.NewCameraController(glutils.NewCamera(
 		mgl32.Vec3{0.0, 0.0, 3.0},
 		mgl32.Vec3{0.0, 1.0, 3.0},
 		glutils.YAW, glutils.PITCH,
-	)
-	lc.lastX = sections.WIDTH / 2.0
-	lc.lastY = sections.HEIGHT / 2.0
+	))
+	// the container and the lamp
+	lc.SetBounds(mgl32.Vec3{-0.9, -0.9, -0.9}, mgl32.Vec3{1.4, 1.2, 2.2})
 
 	// Light attributes
 	lc.lightPos = mgl32.Vec3{1.2, 1.0, 2.0}
 	lc.lightPositionMat = mgl32.Translate3D(lc.lightPos[0], lc.lightPos[1], lc.lightPos[2])
 
-	// Deltatime
-	lc.deltaTime = 0.0 // Time between current frame and last frame
-	lc.lastFrame = 0.0 // Time of last frame
-
 	lc.translationMat = mgl32.Translate3D(0, 0, 0.0)
 	lc.scaleMat = mgl32.Scale3D(0.2, 0.2, 0.2)
 	lc.rotationAxis = mgl32.Vec3{1.0, 0.3, 0.5}.Normalize()
@@ -120,7 +99,7 @@ func (lc *LightingColors) initContainers(vertices []float32) {
 		Normalized: false,
 		Attributes: attr,
 	}
-	lc.containerVa.Setup()
+	lc.SetupVertexArray(&lc.containerVa)
 
 	attr2 := glutils.NewAttributesMap()
 	attr2.Add(lc.lampShader.Attributes["position"], 3, 0)
@@ -131,15 +110,15 @@ func (lc *LightingColors) initContainers(vertices []float32) {
 		Normalized: false,
 		Stride:     3,
 	}
-	lc.lightVa.Setup()
+	lc.SetupVertexArray(&lc.lightVa)
 }
 func (lc *LightingColors) InitGL() error {
 	lc.initCamera()
 	if err := lc.initShaders(
-		"_assets/lighting/1.colors/colors.vs",
-		"_assets/lighting/1.colors/colors.frag",
-		"_assets/lighting/1.colors/lamp.vs",
-		"_assets/lighting/1.colors/lamp.frag",
+		lc.Asset("lighting/1.colors/colors.vs"),
+		lc.Asset("lighting/1.colors/colors.frag"),
+		lc.Asset("lighting/1.colors/lamp.vs"),
+		lc.Asset("lighting/1.colors/lamp.frag"),
 	); err != nil {
 		return err
 	}
@@ -148,23 +127,12 @@ func (lc *LightingColors) InitGL() error {
 	return nil
 }
 
+func (lc *LightingColors) GetSubHeader() string {
+	return lc.Help()
+}
+
 func (lc *LightingColors) Update() {
-	// Set frame time
-	currentFrame := glfw.GetTime()
-	lc.deltaTime = currentFrame - lc.lastFrame
-	lc.lastFrame = currentFrame
-	if lc.w {
-		lc.camera.ProcessKeyboard(glutils.FORWARD, lc.deltaTime)
-	}
-	if lc.s {
-		lc.camera.ProcessKeyboard(glutils.BACKWARD, lc.deltaTime)
-	}
-	if lc.a {
-		lc.camera.ProcessKeyboard(glutils.LEFT, lc.deltaTime)
-	}
-	if lc.d {
-		lc.camera.ProcessKeyboard(glutils.RIGHT, lc.deltaTime)
-	}
+	lc.UpdateCamera(lc.Clock.FrameDelta())
 }
 
 func (lc *LightingColors) clear() {
@@ -179,8 +147,8 @@ func (lc *LightingColors) setLightingUniforms() {
 }
 func (lc *LightingColors) getCameraTransforms() (mgl32.Mat4, mgl32.Mat4) {
 	// Create camera transformations
-	view := lc.camera.GetViewMatrix()
-	projection := mgl32.Perspective(float32(lc.camera.Zoom), sections.Ratio, 0.1, 100.0)
+	view := lc.Camera.GetViewMatrix()
+	projection := lc.Projection(0.1, 100.0)
 	return view, projection
 }
 func (lc *LightingColors) transformShader(shader glutils.Shader, view, projection mgl32.Mat4) {
@@ -190,12 +158,12 @@ func (lc *LightingColors) transformShader(shader glutils.Shader, view, projectio
 }
 func (lc *LightingColors) drawContainer() {
 	// Draw the container (using container's vertex attributes)
-	angle := float32(glfw.GetTime())
+	angle := float32(lc.Clock.Time())
 	model := lc.translationMat.Mul4(mgl32.HomogRotate3D(angle, lc.rotationAxis))
 	gl.UniformMatrix4fv(lc.lightingShader.Uniforms["model"], 1, false, &model[0])
 
 	gl.BindVertexArray(lc.containerVa.Vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	sections.DrawArrays(gl.TRIANGLES, 0, 36)
 	gl.BindVertexArray(0)
 }
 func (lc *LightingColors) drawLamp() {
@@ -204,10 +172,10 @@ func (lc *LightingColors) drawLamp() {
 	gl.UniformMatrix4fv(lc.lampShader.Uniforms["model"], 1, false, &model[0])
 	// Draw the light object (using light's vertex attributes)
 	gl.BindVertexArray(lc.lightVa.Vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	sections.DrawArrays(gl.TRIANGLES, 0, 36)
 	gl.BindVertexArray(0)
 }
-func (lc *LightingColors) Draw() {
+func (lc *LightingColors) Draw(vp sections.Viewport) {
 	lc.clear()
 	gl.UseProgram(lc.lightingShader.Program)
 	lc.setLightingUniforms()
@@ -224,35 +192,8 @@ func (lc *LightingColors) Draw() {
 }
 
 func (lc *LightingColors) Close() {
-	lc.lampShader.Delete()
-	lc.lightingShader.Delete()
-	lc.lightVa.Delete()
-	lc.containerVa.Delete()
-}
-
-func (lc *LightingColors) HandleKeyboard(k glfw.Key, s int, a glfw.Action, mk glfw.ModifierKey, keys map[glfw.Key]bool) {
-	lc.w = keys[glfw.KeyW]
-	lc.a = keys[glfw.KeyA]
-	lc.s = keys[glfw.KeyS]
-	lc.d = keys[glfw.KeyD]
-}
-
-func (lc *LightingColors) HandleMousePosition(xpos, ypos float64) {
-	if lc.firstMouse {
-		lc.lastX = xpos
-		lc.lastY = ypos
-		lc.firstMouse = false
-	}
-
-	xoffset := xpos - lc.lastX
-	yoffset := lc.lastY - ypos // Reversed since y-coordinates go from bottom to left
-
-	lc.lastX = xpos
-	lc.lastY = ypos
-
-	lc.camera.ProcessMouseMovement(xoffset, yoffset, true)
-}
-
-func (lc *LightingColors) HandleScroll(xoff, yoff float64) {
-	lc.camera.ProcessMouseScroll(yoff)
+	lc.DeleteShader(&lc.lampShader)
+	lc.DeleteShader(&lc.lightingShader)
+	lc.DeleteVertexArray(&lc.lightVa)
+	lc.DeleteVertexArray(&lc.containerVa)
 }
@@ -3,16 +3,13 @@ package lighting
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/raedatoui/glutils"
+	"github.com/raedatoui/learn-opengl-golang/sections"
 )
 
 type BasicSpecular struct {
 	LightingColors
 }
 
-func (bc *BasicSpecular) GetHeader() string {
-	return "2. Basic Specular Lighting"
-}
-
 func (lc *BasicSpecular) getVertices() []float32 {
 	return []float32{
 		-0.5, -0.5, -0.5, 0.0, 0.0, -1.0,
@@ -70,7 +67,7 @@ func (bc *BasicSpecular) initContainers(vertices []float32) {
 		Normalized: false,
 		Attributes: attr,
 	}
-	bc.containerVa.Setup()
+	bc.SetupVertexArray(&bc.containerVa)
 
 	attr2 := glutils.NewAttributesMap()
 	attr2.Add(bc.lampShader.Attributes["position"], 3, 0)
@@ -81,22 +78,22 @@ func (bc *BasicSpecular) initContainers(vertices []float32) {
 		Normalized: false,
 		Stride:     6,
 	}
-	bc.lightVa.Setup()
+	bc.SetupVertexArray(&bc.lightVa)
 }
 
 func (bc *BasicSpecular) setLightingUniforms() {
 	gl.Uniform3f(bc.lightingShader.Uniforms["objectColor"], 1.0, 0.5, 0.31)
 	gl.Uniform3f(bc.lightingShader.Uniforms["lightColor"], 1.0, 0.5, 1.0)
 	gl.Uniform3f(bc.lightingShader.Uniforms["lightPos"], bc.lightPos[0], bc.lightPos[1], bc.lightPos[2])
-	gl.Uniform3f(bc.lightingShader.Uniforms["viewPos"], bc.camera.Position[0], bc.camera.Position[1], bc.camera.Position[2])
+	gl.Uniform3f(bc.lightingShader.Uniforms["viewPos"], bc.Camera.Position[0], bc.Camera.Position[1], bc.Camera.Position[2])
 }
 func (bc *BasicSpecular) InitGL() error {
 	bc.initCamera()
 	if err := bc.initShaders(
-		"_assets/lighting/2.basic/lighting.vs",
-		"_assets/lighting/2.basic/lighting.frag",
-		"_assets/lighting/2.basic/lamp.vs",
-		"_assets/lighting/2.basic/lamp.frag",
+		bc.Asset("lighting/2.basic/lighting.vs"),
+		bc.Asset("lighting/2.basic/lighting.frag"),
+		bc.Asset("lighting/2.basic/lamp.vs"),
+		bc.Asset("lighting/2.basic/lamp.frag"),
 	); err != nil {
 		return err
 	}
@@ -104,7 +101,7 @@ func (bc *BasicSpecular) InitGL() error {
 	return nil
 }
 
-func (bc *BasicSpecular) Draw() {
+func (bc *BasicSpecular) Draw(vp sections.Viewport) {
 	bc.clear()
 	gl.UseProgram(bc.lightingShader.Program)
 	bc.setLightingUniforms()
@@ -133,11 +130,11 @@ func (bc *BasicSpecular) Draw() {
 //	gl.Uniform3f(objectColorLoc, 1.0, 0.5, 0.31)
 //	gl.Uniform3f(lightColorLoc, 1.0, 0.5, 1.0)
 //	gl.Uniform3f(lightPosLoc, bc.lightPos[0], bc.lightPos[1], bc.lightPos[2])
-//	gl.Uniform3f(viewPosLoc, bc.camera.Position[0], bc.camera.Position[1], bc.camera.Position[2])
+//	gl.Uniform3f(viewPosLoc, bc.Camera.Position[0], bc.Camera.Position[1], bc.Camera.Position[2])
 //
 //	// Create camera transformations
-//	view := bc.camera.GetViewMatrix()
-//	projection := mgl32.Perspective(float32(bc.camera.Zoom), sections.RATIO, 0.1, 100.0)
+//	view := bc.Camera.GetViewMatrix()
+//	projection := mgl32.Perspective(float32(bc.Camera.Zoom), sections.RATIO, 0.1, 100.0)
 //	// Get the uniform locations
 //	modelLoc := gl.GetUniformLocation(bc.lightingShader, gl.Str("model\x00"))
 //	viewLoc := gl.GetUniformLocation(bc.lightingShader, gl.Str("view\x00"))
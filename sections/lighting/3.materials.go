@@ -4,27 +4,23 @@ import (
 	"math"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/raedatoui/learn-opengl-golang/sections"
 )
 
 type Materials struct {
 	BasicSpecular
 }
 
-func (m *Materials) GetHeader() string {
-	return "2. Basic Specular Lighting"
-}
-
 func (m *Materials) setLightingUniforms() {
 	gl.Uniform3f(m.lightingShader.Uniforms["light.position"], m.lightPos.X(), m.lightPos.Y(), m.lightPos.Z())
-	gl.Uniform3f(m.lightingShader.Uniforms["viewPos"], m.camera.Position.X(), m.camera.Position.Y(), m.camera.Position.Z())
+	gl.Uniform3f(m.lightingShader.Uniforms["viewPos"], m.Camera.Position.X(), m.Camera.Position.Y(), m.Camera.Position.Z())
 
 	// Set lights properties
 	lightColor := mgl32.Vec3{
-		float32(math.Sin(glfw.GetTime() * 2.0)),
-		float32(math.Sin(glfw.GetTime() * 0.7)),
-		float32(math.Sin(glfw.GetTime() * 1.3)),
+		float32(math.Sin(m.Clock.Time() * 2.0)),
+		float32(math.Sin(m.Clock.Time() * 0.7)),
+		float32(math.Sin(m.Clock.Time() * 1.3)),
 	}
 
 	// Decrease the influence
@@ -52,10 +48,10 @@ func (m *Materials) setLightingUniforms() {
 func (m *Materials) InitGL() error {
 	m.initCamera()
 	if err := m.initShaders(
-		"_assets/lighting/3.materials/materials.vs",
-		"_assets/lighting/3.materials/materials.frag",
-		"_assets/lighting/3.materials/lamp.vs",
-		"_assets/lighting/3.materials/lamp.frag",
+		m.Asset("lighting/3.materials/materials.vs"),
+		m.Asset("lighting/3.materials/materials.frag"),
+		m.Asset("lighting/3.materials/lamp.vs"),
+		m.Asset("lighting/3.materials/lamp.frag"),
 	); err != nil {
 		return err
 	}
@@ -63,7 +59,7 @@ func (m *Materials) InitGL() error {
 	return nil
 }
 
-func (m *Materials) Draw() {
+func (m *Materials) Draw(vp sections.Viewport) {
 	m.clear()
 	gl.UseProgram(m.lightingShader.Program)
 	m.setLightingUniforms()
@@ -0,0 +1,17 @@
+package lighting
+
+import "github.com/raedatoui/learn-opengl-golang/sections"
+
+func init() {
+	for _, e := range []sections.Entry{
+		{Order: 10, Name: "1. Colors", Description: "object and light colors",
+			New: func() sections.Slide { return new(LightingColors) }},
+		{Order: 20, Name: "2. Basic Specular Lighting", Description: "ambient, diffuse and specular lighting",
+			New: func() sections.Slide { return new(BasicSpecular) }},
+		{Order: 30, Name: "3. Materials", Description: "material and light properties",
+			New: func() sections.Slide { return new(Materials) }},
+	} {
+		e.Section = sections.Lighting
+		sections.Register(e)
+	}
+}
@@ -0,0 +1,269 @@
+package sections
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/raedatoui/glfont"
+)
+
+const (
+	// FontFile is the font of the headers and the title slides, relative to the assets
+	FontFile = "fonts/huge_agb_v5.ttf"
+	// FontSize is the size in pixels the glyphs are rendered at, Printf scales are relative to it
+	FontSize = 52
+)
+
+// the kinds of markdown blocks
+const (
+	paragraph = iota
+	heading
+	bullet
+	numbered
+)
+
+// span is a run of text with the same style
+type span struct {
+	text       string
+	bold, code bool
+}
+
+// block is a heading, a list item or a paragraph
+type block struct {
+	kind  int
+	level int
+	// marker is the bullet or the number of a list item
+	marker string
+	spans  []span
+}
+
+// parseMarkdown reads the subset of markdown used by the title slides: # headings,
+// - and * bullets, numbered lists, **bold** and `code`. Consecutive lines are joined
+// into paragraphs, blank lines separate them.
+func parseMarkdown(src string) []block {
+	var blocks []block
+	var text []string
+	flush := func() {
+		if len(text) > 0 {
+			blocks = append(blocks, block{kind: paragraph, spans: parseInline(strings.Join(text, " "))})
+			text = nil
+		}
+	}
+	for _, l := range strings.Split(src, "\n") {
+		l = strings.TrimSpace(l)
+		switch {
+		case l == "":
+			flush()
+		case strings.HasPrefix(l, "#"):
+			flush()
+			level := len(l) - len(strings.TrimLeft(l, "#"))
+			blocks = append(blocks, block{kind: heading, level: level, spans: parseInline(strings.TrimSpace(l[level:]))})
+		case strings.HasPrefix(l, "- ") || strings.HasPrefix(l, "* "):
+			flush()
+			blocks = append(blocks, block{kind: bullet, marker: "-", spans: parseInline(l[2:])})
+		case listNumber(l) != "":
+			flush()
+			n := listNumber(l)
+			blocks = append(blocks, block{kind: numbered, marker: n, spans: parseInline(strings.TrimSpace(l[len(n):]))})
+		default:
+			text = append(text, l)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// listNumber returns the "1." prefix of a numbered list item
+func listNumber(l string) string {
+	i := strings.IndexFunc(l, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i <= 0 || !strings.HasPrefix(l[i:], ". ") {
+		return ""
+	}
+	return l[:i+1]
+}
+
+// parseInline splits the text on the ** and ` markers
+func parseInline(s string) []span {
+	var spans []span
+	var cur span
+	for len(s) > 0 {
+		switch {
+		case !cur.code && strings.HasPrefix(s, "**"):
+			spans = append(spans, cur)
+			cur = span{bold: !cur.bold}
+			s = s[2:]
+		case s[0] == '`':
+			spans = append(spans, cur)
+			cur = span{code: !cur.code, bold: cur.bold}
+			s = s[1:]
+		default:
+			cur.text += s[:1]
+			s = s[1:]
+		}
+	}
+	spans = append(spans, cur)
+
+	kept := spans[:0]
+	for _, sp := range spans {
+		if sp.text != "" {
+			kept = append(kept, sp)
+		}
+	}
+	return kept
+}
+
+// widther measures text the way it is printed, glfont.Font does it with the advances of
+// the glyphs it loaded
+type widther interface {
+	Width(scale float32, fs string, argv ...interface{}) float32
+}
+
+// textMeasurer measures text with the glyphs of the font printing it, caching their advances
+type textMeasurer struct {
+	font    widther
+	advance map[rune]float32
+}
+
+func newTextMeasurer(f widther) *textMeasurer {
+	return &textMeasurer{font: f, advance: make(map[rune]float32)}
+}
+
+var measurers = make(map[*glfont.Font]*textMeasurer)
+
+// measurerFor returns the measurer of a font, shared by all the slides printing with it
+func measurerFor(f *glfont.Font) *textMeasurer {
+	if m, ok := measurers[f]; ok {
+		return m
+	}
+	m := newTextMeasurer(f)
+	measurers[f] = m
+	return m
+}
+
+// width returns the width of s printed at scale, in window coordinates
+func (m *textMeasurer) width(s string, scale float32) float32 {
+	w := float32(0)
+	for _, r := range s {
+		a, ok := m.advance[r]
+		if !ok {
+			a = m.font.Width(1, "%s", string(r))
+			m.advance[r] = a
+		}
+		w += a
+	}
+	return w * scale
+}
+
+// run is a piece of text placed on a line
+type run struct {
+	x     float32
+	text  string
+	style span
+}
+
+// textLine is a laid out line, y is its baseline
+type textLine struct {
+	y     float32
+	scale float32
+	runs  []run
+}
+
+// markdownLayout places the blocks in a box, wrapping the lines at its width
+type markdownLayout struct {
+	lines  []textLine
+	height float32
+}
+
+// the scales of the headings by level, and of the text
+var headingScales = []float32{0.85, 0.6, 0.45}
+
+const textScale float32 = 0.38
+
+func layoutMarkdown(blocks []block, m *textMeasurer, width, zoom float32) markdownLayout {
+	var l markdownLayout
+	y := float32(0)
+	for i, b := range blocks {
+		scale := textScale
+		indent := float32(0)
+		switch b.kind {
+		case heading:
+			scale = headingScales[len(headingScales)-1]
+			if b.level <= len(headingScales) {
+				scale = headingScales[b.level-1]
+			}
+		case bullet, numbered:
+			indent = 50
+		}
+		scale *= zoom
+		indent *= zoom
+		lineHeight := FontSize * scale * 1.35
+		if i > 0 {
+			// some room between the blocks, more before the headings
+			y += lineHeight * 0.3
+			if b.kind == heading {
+				y += lineHeight * 0.4
+			}
+		}
+
+		y += lineHeight
+		line := textLine{y: y, scale: scale}
+		if b.marker != "" {
+			line.runs = append(line.runs, run{x: indent - m.width(b.marker+" ", scale), text: b.marker})
+		}
+		x := indent
+		space := m.width(" ", scale)
+		spaced := false
+		for _, sp := range b.spans {
+			for j, word := range strings.Fields(sp.text) {
+				// a span is glued to the previous one unless there is a space between them
+				if x > indent && (j > 0 || spaced || strings.HasPrefix(sp.text, " ")) {
+					x += space
+				}
+				w := m.width(word, scale)
+				if x > indent && x+w > width {
+					l.lines = append(l.lines, line)
+					y += lineHeight
+					line = textLine{y: y, scale: scale}
+					x = indent
+				}
+				line.runs = append(line.runs, run{x: x, text: word, style: sp})
+				x += w
+			}
+			spaced = strings.HasSuffix(sp.text, " ")
+		}
+		l.lines = append(l.lines, line)
+	}
+	l.height = y
+	return l
+}
+
+// fitMarkdown lays the blocks out at the size of the window, shrinking them until they fit
+func fitMarkdown(blocks []block, m *textMeasurer, width, height float32) markdownLayout {
+	// the sizes of the text are meant for a 1280 pixels wide window
+	zoom := width / 1280
+	l := layoutMarkdown(blocks, m, width, zoom)
+	for i := 0; i < 4 && l.height > height; i++ {
+		zoom *= height / l.height
+		l = layoutMarkdown(blocks, m, width, zoom)
+	}
+	return l
+}
+
+func (l markdownLayout) draw(f *glfont.Font, x0, y0 float32) {
+	for _, line := range l.lines {
+		for _, r := range line.runs {
+			x, y := x0+r.x, y0+line.y
+			if r.style.code {
+				f.SetColor(1.0, 0.8, 0.4, 1.0)
+			} else {
+				f.SetColor(1.0, 1.0, 1.0, 1.0)
+			}
+			f.Printf(x, y, line.scale, "%s", r.text)
+			if r.style.bold {
+				// the font has no bold face, printing it twice makes it thicker
+				f.Printf(x+line.scale*2, y, line.scale, "%s", r.text)
+			}
+		}
+	}
+	f.SetColor(1.0, 1.0, 1.0, 1.0)
+}
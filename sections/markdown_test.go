@@ -0,0 +1,191 @@
+package sections
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []span
+	}{
+		{"", []span{}},
+		{"plain text", []span{{text: "plain text"}}},
+		{"a **b** c", []span{{text: "a "}, {text: "b", bold: true}, {text: " c"}}},
+		{"call `gl.Clear` now", []span{{text: "call "}, {text: "gl.Clear", code: true}, {text: " now"}}},
+		{"`a**b`", []span{{text: "a**b", code: true}}},
+		{"**`x`**", []span{{text: "x", bold: true, code: true}}},
+		{"**open", []span{{text: "open", bold: true}}},
+	}
+	for _, tt := range tests {
+		if got := parseInline(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseInline(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestListNumber(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"1. one", "1."},
+		{"12. twelve", "12."},
+		{"1.5 is a number", ""},
+		{". no number", ""},
+		{"a. letter", ""},
+	}
+	for _, tt := range tests {
+		if got := listNumber(tt.in); got != tt.want {
+			t.Errorf("listNumber(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []block
+	}{
+		{"empty", "\n\n", nil},
+		{"headings", "# Title\n  ## Sub **bold**", []block{
+			{kind: heading, level: 1, spans: []span{{text: "Title"}}},
+			{kind: heading, level: 2, spans: []span{{text: "Sub "}, {text: "bold", bold: true}}},
+		}},
+		{"paragraphs", "one\ntwo\n\nthree", []block{
+			{kind: paragraph, spans: []span{{text: "one two"}}},
+			{kind: paragraph, spans: []span{{text: "three"}}},
+		}},
+		{"lists", "intro\n- one\n* two\n1. first\n12. twelfth", []block{
+			{kind: paragraph, spans: []span{{text: "intro"}}},
+			{kind: bullet, marker: "-", spans: []span{{text: "one"}}},
+			{kind: bullet, marker: "-", spans: []span{{text: "two"}}},
+			{kind: numbered, marker: "1.", spans: []span{{text: "first"}}},
+			{kind: numbered, marker: "12.", spans: []span{{text: "twelfth"}}},
+		}},
+		{"not lists", "-dash\n1.5 apples", []block{
+			{kind: paragraph, spans: []span{{text: "-dash 1.5 apples"}}},
+		}},
+	}
+	for _, tt := range tests {
+		if got := parseMarkdown(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseMarkdown(%q) = %+v, want %+v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+// monoFont gives every rune an advance of 10 at scale 1
+type monoFont struct{}
+
+func (monoFont) Width(scale float32, fs string, argv ...interface{}) float32 {
+	return float32(len([]rune(fmt.Sprintf(fs, argv...)))) * 10 * scale
+}
+
+// placed is a run of a laid out line, its x rounded to a tenth of a pixel
+type placed struct {
+	x    float64
+	text string
+}
+
+func placedRuns(l textLine) []placed {
+	var p []placed
+	for _, r := range l.runs {
+		p = append(p, placed{math.Round(float64(r.x)*10) / 10, r.text})
+	}
+	return p
+}
+
+func TestLayoutMarkdown(t *testing.T) {
+	m := newTextMeasurer(monoFont{})
+	// at zoom 1 the text is printed at 0.38, a rune is 3.8 wide
+	tests := []struct {
+		name  string
+		src   string
+		width float32
+		want  [][]placed
+	}{
+		{"fits", "aaaa bbbb", 100, [][]placed{
+			{{0, "aaaa"}, {19, "bbbb"}},
+		}},
+		{"wraps", "aaaa bbbb cccc dddd", 40, [][]placed{
+			{{0, "aaaa"}, {19, "bbbb"}},
+			{{0, "cccc"}, {19, "dddd"}},
+		}},
+		{"long word", "aaaaaaaaaaaa b", 20, [][]placed{
+			{{0, "aaaaaaaaaaaa"}},
+			{{0, "b"}},
+		}},
+		{"glued spans", "**bold**, `code` end", 200, [][]placed{
+			{{0, "bold"}, {15.2, ","}, {22.8, "code"}, {41.8, "end"}},
+		}},
+		{"bullet", "- aaaa bbbb", 90, [][]placed{
+			{{42.4, "-"}, {50, "aaaa"}, {69, "bbbb"}},
+		}},
+		{"bullet wraps at the indent", "- aaaa bbbb", 70, [][]placed{
+			{{42.4, "-"}, {50, "aaaa"}},
+			{{50, "bbbb"}},
+		}},
+	}
+	for _, tt := range tests {
+		l := layoutMarkdown(parseMarkdown(tt.src), m, tt.width, 1)
+		var got [][]placed
+		for _, line := range l.lines {
+			got = append(got, placedRuns(line))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		lineHeight := FontSize * textScale * 1.35
+		for i, line := range l.lines {
+			if want := lineHeight * float32(i+1); math.Abs(float64(line.y-want)) > 0.01 {
+				t.Errorf("%s: line %d at %g, want %g", tt.name, i, line.y, want)
+			}
+		}
+		if l.height != l.lines[len(l.lines)-1].y {
+			t.Errorf("%s: height %g, want the baseline of the last line %g", tt.name, l.height, l.lines[len(l.lines)-1].y)
+		}
+	}
+}
+
+func TestLayoutMarkdownSpacing(t *testing.T) {
+	m := newTextMeasurer(monoFont{})
+	l := layoutMarkdown(parseMarkdown("# Title\ntext\n\nmore"), m, 1000, 1)
+	if len(l.lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(l.lines))
+	}
+	if l.lines[0].scale != headingScales[0] || l.lines[1].scale != textScale {
+		t.Errorf("scales %g and %g, want %g and %g", l.lines[0].scale, l.lines[1].scale, headingScales[0], textScale)
+	}
+	// the paragraphs are spaced by a third of a line
+	lineHeight := FontSize * textScale * 1.35
+	if gap := l.lines[2].y - l.lines[1].y; math.Abs(float64(gap-lineHeight*1.3)) > 0.01 {
+		t.Errorf("paragraphs %g apart, want %g", gap, lineHeight*1.3)
+	}
+}
+
+func TestFitMarkdown(t *testing.T) {
+	m := newTextMeasurer(monoFont{})
+	blocks := parseMarkdown("# Title\n\nsome text under it")
+	full := fitMarkdown(blocks, m, 1280, 1000)
+	if full.lines[0].scale != headingScales[0] {
+		t.Errorf("a layout that fits is scaled by %g, want %g", full.lines[0].scale, headingScales[0])
+	}
+	small := fitMarkdown(blocks, m, 1280, full.height/2)
+	if small.height > full.height/2+0.01 {
+		t.Errorf("fitted in %g, want at most %g", small.height, full.height/2)
+	}
+	if small.lines[0].scale >= full.lines[0].scale {
+		t.Errorf("the heading wasnt shrunk: %g", small.lines[0].scale)
+	}
+}
+
+func TestTextMeasurerCaches(t *testing.T) {
+	m := newTextMeasurer(monoFont{})
+	if w := m.width("abca", 0.5); w != 20 {
+		t.Errorf("width %g, want 20", w)
+	}
+	if len(m.advance) != 3 {
+		t.Errorf("%d advances cached, want 3", len(m.advance))
+	}
+}
@@ -0,0 +1,34 @@
+package sections
+
+import (
+	"unsafe"
+
+	"github.com/raedatoui/glutils"
+)
+
+// modelInfo is measured once when a model is loaded
+type modelInfo struct {
+	meshes int
+	// bytes is the size of the vertices and the indices. glutils doesnt expose the
+	// textures of a model, they arent counted.
+	bytes int
+}
+
+// models are the models loaded with LoadModel and not deleted yet
+var models = make(map[*glutils.Model]modelInfo)
+
+func measureModel(m *glutils.Model) modelInfo {
+	info := modelInfo{meshes: len(m.Meshes)}
+	for _, mesh := range m.Meshes {
+		info.bytes += len(mesh.Vertices) * int(unsafe.Sizeof(mesh.Vertices[0]))
+		info.bytes += len(mesh.Indices) * int(unsafe.Sizeof(mesh.Indices[0]))
+	}
+	return info
+}
+
+// deleteModel frees the meshes and the textures of the model with Dispose
+func deleteModel(m *glutils.Model) {
+	delete(models, m)
+	m.Dispose()
+	*m = glutils.Model{}
+}
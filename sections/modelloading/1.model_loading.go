@@ -1,71 +1,62 @@
 package modelloading
 
 import (
+	"fmt"
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
 	"path"
-	"fmt"
 )
 
 type ModelLoading struct {
 	sections.BaseSketch
-	shader               glutils.Shader
-	model                glutils.Model
-	camera               glutils.Camera
-	deltaTime, lastFrame float64
-	lastX, lastY         float64
-	firstMouse           bool
-	w, a, s, d           bool
+	sections.CameraController
+	shader glutils.Shader
+	model  glutils.Model
 }
 
 func (ml *ModelLoading) InitGL() error {
-	ml.firstMouse = false
-	ml.camera = glutils.NewCamera(
+	ml.CameraController = sections.NewCameraController(glutils.NewCamera(
 		mgl32.Vec3{0.0, 0.0, 3.0},
 		mgl32.Vec3{0.0, 1.0, 3.0},
 		glutils.YAW, glutils.PITCH,
-	)
-	ml.Name = "3. Model Loading"
+	))
+	// the nanosuit once scaled and moved down by the model matrix in Draw
+	ml.SetBounds(mgl32.Vec3{-0.8, -1.75, -0.5}, mgl32.Vec3{0.8, 1.35, 0.5})
 	// Setup and compile our shaders
-	ml.shader, _ = glutils.NewShader("_assets/model_loading/shader.vs",
-		"_assets/model_loading/shader.frag", "")
+	if err := ml.LoadShader(&ml.shader, ml.Asset("model_loading/shader.vs"),
+		ml.Asset("model_loading/shader.frag"), ""); err != nil {
+		return err
+	}
 	// Load models
-	ml.model, _ = glutils.NewModel("_assets/objects/nanosuit/", "nanosuit.obj", false)
-	gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
-	return nil
+	return ml.LoadModel(&ml.model, ml.Asset("objects/nanosuit")+"/", "nanosuit.obj", false)
+}
+
+// RenderState draws the model in wireframe
+func (ml *ModelLoading) RenderState() sections.RenderState {
+	r := ml.BaseSketch.RenderState()
+	r.PolygonMode = gl.LINE
+	return r
+}
+
+func (ml *ModelLoading) GetSubHeader() string {
+	return ml.Help()
 }
 
 func (ml *ModelLoading) Update() {
-	// Set frame time
-	currentFrame := glfw.GetTime()
-	ml.deltaTime = currentFrame - ml.lastFrame
-	ml.lastFrame = currentFrame
-	if ml.w {
-		ml.camera.ProcessKeyboard(glutils.FORWARD, ml.deltaTime)
-	}
-	if ml.s {
-		ml.camera.ProcessKeyboard(glutils.BACKWARD, ml.deltaTime)
-	}
-	if ml.a {
-		ml.camera.ProcessKeyboard(glutils.LEFT, ml.deltaTime)
-	}
-	if ml.d {
-		ml.camera.ProcessKeyboard(glutils.RIGHT, ml.deltaTime)
-	}
+	ml.UpdateCamera(ml.Clock.FrameDelta())
 }
 
-func (ml *ModelLoading) Draw() {
+func (ml *ModelLoading) Draw(vp sections.Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(ml.Color32.R, ml.Color32.G, ml.Color32.B, ml.Color32.A)
 
 	gl.UseProgram(ml.shader.Program)
 
 	// Transformation matrices
-	projection := mgl32.Perspective(float32(ml.camera.Zoom), sections.Ratio, 0.1, 100.0)
-	view := ml.camera.GetViewMatrix()
+	projection := ml.Projection(0.1, 100.0)
+	view := ml.Camera.GetViewMatrix()
 
 	gl.UniformMatrix4fv(ml.shader.Uniforms["view"], 1, false, &view[0])
 	gl.UniformMatrix4fv(ml.shader.Uniforms["projection"], 1, false, &projection[0])
@@ -75,46 +66,22 @@ func (ml *ModelLoading) Draw() {
 	model = model.Mul4(mgl32.Scale3D(0.2, 0.2, 0.2)) // It's a bit too big for our scene, so scale it down
 
 	gl.UniformMatrix4fv(ml.shader.Uniforms["model"], 1, false, &model[0])
-	ml.model.Draw(ml.shader.Program)
-}
-
-func (lc *ModelLoading) HandleMousePosition(xpos, ypos float64) {
-	if lc.firstMouse {
-		lc.lastX = xpos
-		lc.lastY = ypos
-		lc.firstMouse = false
-	}
-
-	xoffset := xpos - lc.lastX
-	yoffset := lc.lastY - ypos // Reversed since y-coordinates go from bottom to left
-
-	lc.lastX = xpos
-	lc.lastY = ypos
-
-	lc.camera.ProcessMouseMovement(xoffset, yoffset, true)
-}
-
-func (ml *ModelLoading) HandleKeyboard(k glfw.Key, s int, a glfw.Action, mk glfw.ModifierKey, keys map[glfw.Key]bool) {
-	ml.w = keys[glfw.KeyW]
-	ml.a = keys[glfw.KeyA]
-	ml.s = keys[glfw.KeyS]
-	ml.d = keys[glfw.KeyD]
-}
-
-func (ml *ModelLoading) HandleScroll(xoff, yoff float64) {
-	ml.camera.ProcessMouseScroll(yoff)
+	sections.DrawModel(&ml.model, ml.shader.Program)
 }
 
 func (ml *ModelLoading) HandleFiles(names []string) {
 	f := path.Base(names[0])
 	dir := path.Dir(names[0]) + "/"
 	fmt.Println(f, dir)
-	ml.model.Dispose()
-	ml.model, _ = glutils.NewModel(dir, f, false)
+	ml.DeleteModel(&ml.model)
+	if err := ml.LoadModel(&ml.model, dir, f, false); err != nil {
+		ml.Log.Printf("cant load %s: %v", names[0], err)
+	}
 	//gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
 }
 
 func (ml *ModelLoading) Close() {
-	ml.model.Dispose()
+	ml.DeleteModel(&ml.model)
+	ml.DeleteShader(&ml.shader)
 	gl.UseProgram(0)
 }
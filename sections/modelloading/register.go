@@ -0,0 +1,12 @@
+package modelloading
+
+import "github.com/raedatoui/learn-opengl-golang/sections"
+
+func init() {
+	sections.Register(sections.Entry{
+		Section: sections.ModelLoading, Order: 10,
+		Name:        "3. Model Loading",
+		Description: "nanosuit loaded with assimp, drop a model file to load it",
+		New:         func() sections.Slide { return new(ModelLoading) },
+	})
+}
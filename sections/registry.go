@@ -0,0 +1,107 @@
+package sections
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Numbers of the sections of the learnopengl.com tutorial
+const (
+	Installation = iota
+	GettingStarted
+	Lighting
+	ModelLoading
+	AdvancedOpenGL
+	AdvancedLighting
+	PBR
+	InPractice
+)
+
+// Transition is the effect played when switching to a slide
+type Transition int
+
+const (
+	// Crossfade blends the slides, it is the default
+	Crossfade Transition = iota
+	// SlideIn pushes the previous slide out of the window
+	SlideIn
+	// Wipe reveals the next slide behind an edge sweeping across the window
+	Wipe
+	// Cut switches instantly
+	Cut
+)
+
+var transitionNames = []string{"crossfade", "slide", "wipe", "none"}
+
+func (t Transition) String() string {
+	if t < 0 || int(t) >= len(transitionNames) {
+		return fmt.Sprintf("Transition(%d)", int(t))
+	}
+	return transitionNames[t]
+}
+
+// ParseTransition returns the transition named crossfade, slide, wipe or none
+func ParseTransition(name string) (Transition, error) {
+	for i, n := range transitionNames {
+		if n == name {
+			return Transition(i), nil
+		}
+	}
+	return Cut, fmt.Errorf("unknown transition %q", name)
+}
+
+// Section is a chapter of the tutorial, every section gets a cover slide
+type Section struct {
+	Number int
+	Title  string
+	// Transition is played when switching to the slides of the section
+	Transition Transition
+}
+
+// Sections lists the chapters of the tutorial in order. Sections without registered slides
+// only show their cover.
+var Sections = []Section{
+	{Installation, "Test installation using go-gl", Crossfade},
+	{GettingStarted, "Getting Started", SlideIn},
+	{Lighting, "Lighting", Crossfade},
+	{ModelLoading, "Model Loading", Wipe},
+	{AdvancedOpenGL, "Advanced OpenGL", SlideIn},
+	{AdvancedLighting, "Advanced Lighting", Crossfade},
+	{PBR, "PBR", Crossfade},
+	{InPractice, "In Practice", SlideIn},
+}
+
+// Entry describes a slide registered by one of the tutorial packages
+type Entry struct {
+	// Section is the number of the section the slide belongs to
+	Section int
+	// Order sorts the slides within their section
+	Order int
+	// Name is the header shown on the slide
+	Name        string
+	Description string
+	// New creates a new instance of the slide
+	New func() Slide
+}
+
+var registry []Entry
+
+// Register adds a slide to the registry. It is meant to be called from the init function
+// of the package implementing the slide.
+func Register(e Entry) {
+	registry = append(registry, e)
+}
+
+// Entries returns the slides registered for a section, sorted by their order
+func Entries(section int) []Entry {
+	var entries []Entry
+	for _, e := range registry {
+		if e.Section == section {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Order < entries[j].Order
+	})
+	return entries
+}
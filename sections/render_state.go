@@ -0,0 +1,59 @@
+package sections
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/glutils"
+)
+
+// RenderState is the fixed function GL state a slide draws with. The main loop applies
+// the state of the current slide before drawing it and the defaults before drawing the
+// text on top, so a slide cant leak its state to the next one.
+type RenderState struct {
+	DepthTest bool
+	// Blend uses the usual SRC_ALPHA, ONE_MINUS_SRC_ALPHA function
+	Blend    bool
+	CullFace bool
+	// PolygonMode is gl.FILL, gl.LINE or gl.POINT
+	PolygonMode uint32
+	PointSize   float32
+	ClearColor  glutils.Color32
+}
+
+// DefaultRenderState is the state most slides use and the text is drawn with
+func DefaultRenderState() RenderState {
+	return RenderState{
+		DepthTest:   true,
+		Blend:       true,
+		PolygonMode: gl.FILL,
+		PointSize:   1.0,
+	}
+}
+
+// OverlayRenderState is the state of what is drawn over the slides, like the HUD, without
+// the depth test
+func OverlayRenderState() RenderState {
+	r := DefaultRenderState()
+	r.DepthTest = false
+	return r
+}
+
+// Apply sets the GL state
+func (r RenderState) Apply() {
+	enable(gl.DEPTH_TEST, r.DepthTest)
+	enable(gl.BLEND, r.Blend)
+	if r.Blend {
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	}
+	enable(gl.CULL_FACE, r.CullFace)
+	gl.PolygonMode(gl.FRONT_AND_BACK, r.PolygonMode)
+	gl.PointSize(r.PointSize)
+	gl.ClearColor(r.ClearColor.R, r.ClearColor.G, r.ClearColor.B, r.ClearColor.A)
+}
+
+func enable(cap uint32, on bool) {
+	if on {
+		gl.Enable(cap)
+	} else {
+		gl.Disable(cap)
+	}
+}
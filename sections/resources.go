@@ -0,0 +1,297 @@
+package sections
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/glutils"
+)
+
+type resourceKind int
+
+const (
+	bufferResource resourceKind = iota
+	vertexArrayResource
+	textureResource
+	programResource
+	framebufferResource
+	renderbufferResource
+	modelResource
+)
+
+var resourceNames = [...]string{"buffer", "vertex array", "texture", "program", "framebuffer", "renderbuffer", "model"}
+
+// resource is a GL object created by a slide through the helpers below, or a model
+type resource struct {
+	kind  resourceKind
+	id    uint32
+	model *glutils.Model
+}
+
+// resourceKey names a GL object
+type resourceKey struct {
+	kind resourceKind
+	id   uint32
+}
+
+// owners maps the objects tracked by all the slides to the slide that created them. GL only
+// hands a name out again once its object is deleted, so a name tracked twice was deleted
+// without the helpers and the first record is stale.
+var owners = make(map[resourceKey]*BaseSlide)
+
+// deleteResource frees a released object, the tests replace it to run without GL
+var deleteResource = resource.delete
+
+func (r resource) delete() {
+	switch r.kind {
+	case bufferResource:
+		gl.DeleteBuffers(1, &r.id)
+	case vertexArrayResource:
+		gl.DeleteVertexArrays(1, &r.id)
+	case textureResource:
+		gl.DeleteTextures(1, &r.id)
+	case programResource:
+		gl.DeleteProgram(r.id)
+	case framebufferResource:
+		gl.DeleteFramebuffers(1, &r.id)
+	case renderbufferResource:
+		gl.DeleteRenderbuffers(1, &r.id)
+	case modelResource:
+		deleteModel(r.model)
+	}
+}
+
+func (s *BaseSlide) track(kind resourceKind, id uint32) uint32 {
+	if id == 0 {
+		return id
+	}
+	key := resourceKey{kind, id}
+	if o, ok := owners[key]; ok {
+		if o.debug && o.Log != nil {
+			o.Log.Printf("%s deleted %s %d without the helpers", o.Name, resourceNames[kind], id)
+		}
+		o.forget(key)
+	}
+	owners[key] = s
+	s.resources = append(s.resources, resource{kind: kind, id: id})
+	return id
+}
+
+// untrack forgets an object the slide deleted, GL hands its name out again to the next
+// object created, which isnt the slide's to free
+func (s *BaseSlide) untrack(kind resourceKind, id uint32) {
+	key := resourceKey{kind, id}
+	if id == 0 || owners[key] != s {
+		return
+	}
+	delete(owners, key)
+	s.forget(key)
+}
+
+// forget removes the record of an object
+func (s *BaseSlide) forget(key resourceKey) {
+	for i, r := range s.resources {
+		if r.kind == key.kind && r.id == key.id {
+			s.resources = append(s.resources[:i], s.resources[i+1:]...)
+			return
+		}
+	}
+}
+
+// release deletes the objects and forgets them
+func (s *BaseSlide) release(kind resourceKind, ids []uint32) {
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+		s.untrack(kind, id)
+		deleteResource(resource{kind: kind, id: id})
+	}
+}
+
+// GenBuffer creates a buffer that is freed when the slide closes
+func (s *BaseSlide) GenBuffer() uint32 {
+	var id uint32
+	gl.GenBuffers(1, &id)
+	return s.track(bufferResource, id)
+}
+
+// GenVertexArray creates a vertex array that is freed when the slide closes
+func (s *BaseSlide) GenVertexArray() uint32 {
+	var id uint32
+	gl.GenVertexArrays(1, &id)
+	return s.track(vertexArrayResource, id)
+}
+
+// GenFramebuffer creates a framebuffer that is freed when the slide closes
+func (s *BaseSlide) GenFramebuffer() uint32 {
+	var id uint32
+	gl.GenFramebuffers(1, &id)
+	return s.track(framebufferResource, id)
+}
+
+// GenRenderbuffer creates a renderbuffer that is freed when the slide closes
+func (s *BaseSlide) GenRenderbuffer() uint32 {
+	var id uint32
+	gl.GenRenderbuffers(1, &id)
+	return s.track(renderbufferResource, id)
+}
+
+// NewTexture loads a texture with glutils.NewTexture and frees it when the slide closes
+func (s *BaseSlide) NewTexture(wrapS, wrapT, minFilter, magFilter int32, file string) (uint32, error) {
+	id, err := glutils.NewTexture(wrapS, wrapT, minFilter, magFilter, file)
+	if err != nil {
+		return 0, err
+	}
+	return s.track(textureResource, id), nil
+}
+
+// NewProgram links a program with glutils.BasicProgram and frees it when the slide closes
+func (s *BaseSlide) NewProgram(vertex, fragment string) (uint32, error) {
+	id, err := glutils.BasicProgram(vertex, fragment)
+	if err != nil {
+		return 0, err
+	}
+	return s.track(programResource, id), nil
+}
+
+// SetupVertexArray calls va.Setup and frees the buffers and the vertex array when the slide closes
+func (s *BaseSlide) SetupVertexArray(va *glutils.VertexArray) {
+	va.Setup()
+	s.track(vertexArrayResource, va.Vao)
+	s.track(bufferResource, va.Vbo)
+	s.track(bufferResource, va.Ebo)
+}
+
+// LoadModel loads a model with glutils.NewModel into dst, its meshes and textures are freed
+// when the slide closes
+func (s *BaseSlide) LoadModel(dst *glutils.Model, dir, file string, gamma bool) error {
+	m, err := glutils.NewModel(dir, file, gamma)
+	if err != nil {
+		return err
+	}
+	*dst = m
+	models[dst] = measureModel(dst)
+	s.resources = append(s.resources, resource{kind: modelResource, model: dst})
+	return nil
+}
+
+// The objects created through the helpers are deleted with the ones below, which forget
+// them. Deleting them directly with gl would leave their names tracked, and GL reuses names.
+
+func (s *BaseSlide) DeleteBuffers(ids ...uint32) {
+	s.release(bufferResource, ids)
+}
+
+func (s *BaseSlide) DeleteVertexArrays(ids ...uint32) {
+	s.release(vertexArrayResource, ids)
+}
+
+func (s *BaseSlide) DeleteTextures(ids ...uint32) {
+	s.release(textureResource, ids)
+}
+
+func (s *BaseSlide) DeletePrograms(ids ...uint32) {
+	s.release(programResource, ids)
+}
+
+func (s *BaseSlide) DeleteFramebuffers(ids ...uint32) {
+	s.release(framebufferResource, ids)
+}
+
+func (s *BaseSlide) DeleteRenderbuffers(ids ...uint32) {
+	s.release(renderbufferResource, ids)
+}
+
+// DeleteShader deletes the program of a shader loaded with LoadShader, it isnt watched anymore
+func (s *BaseSlide) DeleteShader(sh *glutils.Shader) {
+	s.untrack(programResource, sh.Program)
+	delete(s.shaders, sh)
+	sh.Delete()
+}
+
+// DeleteVertexArray deletes the objects of a vertex array set up with SetupVertexArray
+func (s *BaseSlide) DeleteVertexArray(va *glutils.VertexArray) {
+	s.untrack(vertexArrayResource, va.Vao)
+	s.untrack(bufferResource, va.Vbo)
+	s.untrack(bufferResource, va.Ebo)
+	va.Delete()
+}
+
+// DeleteModel frees a model loaded with LoadModel
+func (s *BaseSlide) DeleteModel(m *glutils.Model) {
+	for i, r := range s.resources {
+		if r.kind == modelResource && r.model == m {
+			s.resources = append(s.resources[:i], s.resources[i+1:]...)
+			break
+		}
+	}
+	deleteResource(resource{kind: modelResource, model: m})
+}
+
+// size estimates the memory used by the object, binding it to query GL and restoring the
+// previous binding. Vertex arrays, programs and framebuffers are left out, models count
+// their vertices and indices as measured when they were loaded.
+func (r resource) size() int {
+	var w, h, n int32
+	switch r.kind {
+	case bufferResource:
+		var prev int32
+		gl.GetIntegerv(gl.ARRAY_BUFFER_BINDING, &prev)
+		gl.BindBuffer(gl.ARRAY_BUFFER, r.id)
+		gl.GetBufferParameteriv(gl.ARRAY_BUFFER, gl.BUFFER_SIZE, &n)
+		gl.BindBuffer(gl.ARRAY_BUFFER, uint32(prev))
+		return int(n)
+	case textureResource:
+		var prev int32
+		gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &prev)
+		gl.BindTexture(gl.TEXTURE_2D, r.id)
+		gl.GetTexLevelParameteriv(gl.TEXTURE_2D, 0, gl.TEXTURE_WIDTH, &w)
+		gl.GetTexLevelParameteriv(gl.TEXTURE_2D, 0, gl.TEXTURE_HEIGHT, &h)
+		gl.BindTexture(gl.TEXTURE_2D, uint32(prev))
+		// RGBA, and a third more for the mipmaps
+		return int(w) * int(h) * 4 * 4 / 3
+	case renderbufferResource:
+		var prev int32
+		gl.GetIntegerv(gl.RENDERBUFFER_BINDING, &prev)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, r.id)
+		gl.GetRenderbufferParameteriv(gl.RENDERBUFFER, gl.RENDERBUFFER_WIDTH, &w)
+		gl.GetRenderbufferParameteriv(gl.RENDERBUFFER, gl.RENDERBUFFER_HEIGHT, &h)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, uint32(prev))
+		return int(w) * int(h) * 4
+	case modelResource:
+		return models[r.model].bytes
+	}
+	return 0
+}
+
+func (s *BaseSlide) trackedMemory() int {
+	total := 0
+	for _, r := range s.resources {
+		total += r.size()
+	}
+	return total
+}
+
+// GPUMemory estimates the bytes held by the buffers, textures, renderbuffers and models the
+// slide created through the helpers. Objects created otherwise arent counted.
+func GPUMemory(s Slide) int {
+	if t, ok := s.(interface{ trackedMemory() int }); ok {
+		return t.trackedMemory()
+	}
+	return 0
+}
+
+// ReleaseResources frees the objects created through the helpers that Close didnt delete
+// through them. In debug mode every one of them is logged, Close is expected to have freed them.
+func (s *BaseSlide) ReleaseResources() {
+	for i := len(s.resources) - 1; i >= 0; i-- {
+		r := s.resources[i]
+		if s.debug && s.Log != nil {
+			s.Log.Printf("%s leaked %s %d", s.Name, resourceNames[r.kind], r.id)
+		}
+		if r.kind != modelResource {
+			delete(owners, resourceKey{r.kind, r.id})
+		}
+		deleteResource(r)
+	}
+	s.resources = nil
+}
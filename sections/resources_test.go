@@ -0,0 +1,150 @@
+package sections
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/raedatoui/glutils"
+)
+
+// fakeDeletes replaces the GL deletes for the test and returns the objects deleted
+func fakeDeletes(t *testing.T) *[]resource {
+	var deleted []resource
+	prev := deleteResource
+	deleteResource = func(r resource) { deleted = append(deleted, r) }
+	t.Cleanup(func() {
+		deleteResource = prev
+		owners = make(map[resourceKey]*BaseSlide)
+	})
+	return &deleted
+}
+
+func TestTrack(t *testing.T) {
+	fakeDeletes(t)
+	s := &BaseSlide{Name: "s"}
+	s.track(bufferResource, 1)
+	s.track(textureResource, 1)
+	s.track(bufferResource, 0)
+
+	want := []resource{{kind: bufferResource, id: 1}, {kind: textureResource, id: 1}}
+	if !reflect.DeepEqual(s.resources, want) {
+		t.Fatalf("tracked %v, want %v", s.resources, want)
+	}
+	if owners[resourceKey{bufferResource, 1}] != s || owners[resourceKey{textureResource, 1}] != s {
+		t.Errorf("the slide doesnt own its objects: %v", owners)
+	}
+
+	s.untrack(bufferResource, 1)
+	want = want[1:]
+	if !reflect.DeepEqual(s.resources, want) {
+		t.Errorf("after untrack %v, want %v", s.resources, want)
+	}
+	if _, ok := owners[resourceKey{bufferResource, 1}]; ok {
+		t.Error("the untracked buffer still has an owner")
+	}
+}
+
+func TestTrackReusedName(t *testing.T) {
+	fakeDeletes(t)
+	var out bytes.Buffer
+	a := &BaseSlide{Name: "a", debug: true, Log: log.New(&out, "", 0)}
+	b := &BaseSlide{Name: "b"}
+
+	// a deleted texture 5 with gl.DeleteTextures, GL gave the name to b
+	a.track(textureResource, 5)
+	a.track(bufferResource, 6)
+	b.track(textureResource, 5)
+
+	if want := []resource{{kind: bufferResource, id: 6}}; !reflect.DeepEqual(a.resources, want) {
+		t.Errorf("a still tracks %v, want %v", a.resources, want)
+	}
+	if owners[resourceKey{textureResource, 5}] != b {
+		t.Error("texture 5 isnt owned by b")
+	}
+	if !strings.Contains(out.String(), "a deleted texture 5 without the helpers") {
+		t.Errorf("the stale record wasnt logged: %q", out.String())
+	}
+
+	// a cant forget what b owns
+	a.untrack(textureResource, 5)
+	if len(b.resources) != 1 || owners[resourceKey{textureResource, 5}] != b {
+		t.Error("a untracked the texture of b")
+	}
+}
+
+func TestRelease(t *testing.T) {
+	deleted := fakeDeletes(t)
+	s := &BaseSlide{Name: "s"}
+	s.track(textureResource, 1)
+	s.track(textureResource, 2)
+	s.track(bufferResource, 3)
+
+	s.DeleteTextures(2, 0)
+	if want := []resource{{kind: textureResource, id: 2}}; !reflect.DeepEqual(*deleted, want) {
+		t.Errorf("deleted %v, want %v", *deleted, want)
+	}
+	want := []resource{{kind: textureResource, id: 1}, {kind: bufferResource, id: 3}}
+	if !reflect.DeepEqual(s.resources, want) {
+		t.Errorf("tracked %v, want %v", s.resources, want)
+	}
+}
+
+func TestReleaseResourcesLeftovers(t *testing.T) {
+	deleted := fakeDeletes(t)
+	var out bytes.Buffer
+	s := &BaseSlide{Name: "s", debug: true, Log: log.New(&out, "", 0)}
+	s.track(bufferResource, 1)
+	s.track(vertexArrayResource, 2)
+	s.track(programResource, 3)
+	s.untrack(vertexArrayResource, 2)
+
+	s.ReleaseResources()
+	// the leftovers are freed in the reverse order they were created
+	want := []resource{{kind: programResource, id: 3}, {kind: bufferResource, id: 1}}
+	if !reflect.DeepEqual(*deleted, want) {
+		t.Errorf("deleted %v, want %v", *deleted, want)
+	}
+	if len(s.resources) != 0 || len(owners) != 0 {
+		t.Errorf("left %v tracked and %v owned", s.resources, owners)
+	}
+	if got := out.String(); got != "s leaked program 3\ns leaked buffer 1\n" {
+		t.Errorf("logged %q", got)
+	}
+
+	// releasing twice frees nothing
+	*deleted = nil
+	s.ReleaseResources()
+	if len(*deleted) != 0 {
+		t.Errorf("deleted %v again", *deleted)
+	}
+}
+
+func TestModelTracking(t *testing.T) {
+	deleted := fakeDeletes(t)
+	m := &glutils.Model{Meshes: []glutils.Mesh{
+		{Vertices: make([]glutils.Vertex, 3), Indices: make([]uint32, 6)},
+		{Vertices: make([]glutils.Vertex, 4)},
+	}}
+	info := measureModel(m)
+	vertex := int(unsafe.Sizeof(glutils.Vertex{}))
+	if want := (modelInfo{meshes: 2, bytes: 7*vertex + 6*4}); info != want {
+		t.Errorf("measured %+v, want %+v", info, want)
+	}
+
+	s := &BaseSlide{Name: "s"}
+	models[m] = info
+	s.resources = append(s.resources, resource{kind: modelResource, model: m})
+	if got := GPUMemory(s); got != info.bytes {
+		t.Errorf("GPUMemory %d, want %d", got, info.bytes)
+	}
+
+	s.DeleteModel(m)
+	if len(s.resources) != 0 || len(*deleted) != 1 || (*deleted)[0].model != m {
+		t.Errorf("tracked %v, deleted %v", s.resources, *deleted)
+	}
+	delete(models, m)
+}
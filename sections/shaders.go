@@ -0,0 +1,111 @@
+package sections
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/raedatoui/glutils"
+)
+
+// watchedShader remembers the files a shader was compiled from and when they were last modified
+type watchedShader struct {
+	files    [3]string
+	modTimes [3]time.Time
+}
+
+func (w *watchedShader) stat() bool {
+	changed := false
+	for i, f := range w.files {
+		if f == "" {
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(w.modTimes[i]) {
+			w.modTimes[i] = info.ModTime()
+			changed = true
+		}
+	}
+	return changed
+}
+
+// LoadShader compiles a shader into dst and watches its source files so ReloadShaders
+// can recompile it when they change. geometry can be empty. The program is freed when
+// the slide closes.
+func (s *BaseSlide) LoadShader(dst *glutils.Shader, vertex, fragment, geometry string) error {
+	sh, err := glutils.NewShader(vertex, fragment, geometry)
+	if err != nil {
+		return err
+	}
+	*dst = sh
+	s.track(programResource, sh.Program)
+
+	if s.shaders == nil {
+		s.shaders = make(map[*glutils.Shader]*watchedShader)
+	}
+	w := &watchedShader{files: [3]string{vertex, fragment, geometry}}
+	w.stat()
+	s.shaders[dst] = w
+	return nil
+}
+
+// ReloadShaders recompiles the shaders whose files changed since they were loaded and
+// reports whether any did. The attributes keep the locations the slide built its vertex
+// arrays with. A shader that fails to compile, or that moves an attribute with a layout
+// qualifier, keeps its previous program and the error is returned.
+func (s *BaseSlide) ReloadShaders() (bool, error) {
+	changed := false
+	var failed error
+	for dst, w := range s.shaders {
+		if !w.stat() {
+			continue
+		}
+		changed = true
+		sh, err := glutils.NewShader(w.files[0], w.files[1], w.files[2])
+		if err != nil {
+			failed = err
+			continue
+		}
+		if err := keepLocations(dst, &sh, w.files[0]); err != nil {
+			sh.Delete()
+			failed = err
+			continue
+		}
+		s.untrack(programResource, dst.Program)
+		dst.Delete()
+		*dst = sh
+		s.track(programResource, sh.Program)
+	}
+	return changed, failed
+}
+
+// keepLocations relinks sh, compiled from file, with the attribute locations of old so the vertex arrays built
+// against old still feed the right inputs, then looks its locations up again
+func keepLocations(old, sh *glutils.Shader, file string) error {
+	for name, loc := range old.Attributes {
+		gl.BindAttribLocation(sh.Program, loc, gl.Str(name+"\x00"))
+	}
+	gl.LinkProgram(sh.Program)
+	var status int32
+	gl.GetProgramiv(sh.Program, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		return fmt.Errorf("relinking %s failed", file)
+	}
+
+	for name := range sh.Attributes {
+		sh.Attributes[name] = uint32(gl.GetAttribLocation(sh.Program, gl.Str(name+"\x00")))
+	}
+	for name := range sh.Uniforms {
+		sh.Uniforms[name] = gl.GetUniformLocation(sh.Program, gl.Str(name+"\x00"))
+	}
+	for name, loc := range old.Attributes {
+		if l, ok := sh.Attributes[name]; ok && l != loc {
+			return fmt.Errorf("%s: %s moved from location %d to %d, restart the slide to rebuild its vertex arrays", file, name, loc, l)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package sections
+
+import "encoding/json"
+
+// Stateful is implemented by slides keeping some state between visits and between runs,
+// like the camera pose or a tweaked value. LoadState is called after InitGL with what
+// SaveState returned when the slide was left, ResetState goes back to the defaults.
+type Stateful interface {
+	SaveState() (json.RawMessage, error)
+	LoadState(data json.RawMessage) error
+	ResetState()
+}
@@ -2,56 +2,68 @@ package sections
 
 import (
 	"errors"
-	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/raedatoui/glfont"
-	"github.com/raedatoui/glutils"
+	"os"
 	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
 )
 
+// the margins of the title slides, the bottom one leaves room for the color
+const (
+	titleMargin = 30
+	titleTop    = 40
+	titleBottom = 50
+)
+
+// TitleSlide shows a page of markdown, like the covers of the presentation and of the sections.
+// Its name is the heading and Markdown follows it, the covers build it from the registry.
+// The prose of File in the assets, when it exists, goes between them.
 type TitleSlide struct {
 	BaseSlide
-	font  *glfont.Font
-	lines []string
+	File     string
+	Markdown string
+	blocks   []block
+	measurer *textMeasurer
+	layout   markdownLayout
 }
 
-func (s *TitleSlide) Init(a ...interface{}) error {
-	f, ok := a[0].(*glfont.Font)
-	if ok == false {
-		return errors.New("first argument isnt a font")
-	}
-	s.font = f
-
-	c, ok := a[1].(glutils.Color)
-	if ok == false {
-		return errors.New("second argument isnt a ColorA")
+func (s *TitleSlide) Init(ctx Context) error {
+	if err := s.BaseSlide.Init(ctx); err != nil {
+		return err
 	}
-	s.Color = c
-	s.Color32 = c.To32()
-	s.ColorHex = glutils.Rgb2Hex(c)
-
-	n, ok := a[2].(string)
-	if ok == false {
-		return errors.New("third argument isnt a string")
+	if s.Font == nil {
+		return errors.New("title slides need a font")
 	}
-	s.Name = n
+	s.Name = ctx.Title
 
-	if strings.Contains(s.Name, "\n") {
-		s.lines = strings.Split(s.Name, "\n")
-	} else {
-		s.lines = []string{s.Name}
+	parts := []string{"# " + s.Name}
+	if s.File != "" {
+		b, err := os.ReadFile(s.Asset(s.File))
+		if err == nil {
+			parts = append(parts, string(b))
+		} else if !os.IsNotExist(err) {
+			return err
+		}
 	}
-
+	parts = append(parts, s.Markdown)
+	s.blocks = parseMarkdown(strings.Join(parts, "\n\n"))
+	s.measurer = measurerFor(s.Font)
+	s.HandleResize(ctx.Viewport)
 	return nil
 }
 
-func (s *TitleSlide) Draw() {
+// HandleResize wraps and scales the text to the window
+func (s *TitleSlide) HandleResize(vp Viewport) {
+	w := float32(vp.WindowWidth) - 2*titleMargin
+	h := float32(vp.WindowHeight) - titleTop - titleBottom
+	s.layout = fitMarkdown(s.blocks, s.measurer, w, h)
+}
+
+func (s *TitleSlide) Draw(vp Viewport) {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.ClearColor(s.Color32.R, s.Color32.G, s.Color32.B, s.Color32.A)
 
-	s.font.SetColor(1.0, 1.0, 1.0, 1.0)
-	for i := 0; i < len(s.lines); i++ {
-		s.font.Printf(30, 100+60*float32(i), 0.85, s.lines[i])
-	}
+	s.layout.draw(s.Font, titleMargin, titleTop)
 }
 
 func (b *TitleSlide) DrawText() bool {
@@ -0,0 +1,52 @@
+package sections
+
+// Viewport is the area a slide draws into. It is passed to Draw every frame and to
+// HandleResize when it changes, so the same slide can draw in a window, offscreen
+// or in a thumbnail of a different size.
+type Viewport struct {
+	// Width and Height are the size of the framebuffer in pixels, what gl.Viewport takes
+	Width, Height int
+	// WindowWidth and WindowHeight are the size of the window in screen coordinates,
+	// the unit of the cursor positions and the text layout
+	WindowWidth, WindowHeight int
+	// ScaleX and ScaleY are the framebuffer pixels per screen coordinate, 2 on most HiDPI screens
+	ScaleX, ScaleY float64
+}
+
+// NewViewport creates a viewport from the framebuffer and window sizes
+func NewViewport(width, height, windowWidth, windowHeight int) Viewport {
+	v := Viewport{
+		Width:        width,
+		Height:       height,
+		WindowWidth:  windowWidth,
+		WindowHeight: windowHeight,
+		ScaleX:       1,
+		ScaleY:       1,
+	}
+	if windowWidth > 0 && windowHeight > 0 {
+		v.ScaleX = float64(width) / float64(windowWidth)
+		v.ScaleY = float64(height) / float64(windowHeight)
+	}
+	return v
+}
+
+// Aspect is the width over height ratio used by the projections
+func (v Viewport) Aspect() float32 {
+	if v.Height == 0 {
+		return 1
+	}
+	return float32(v.Width) / float32(v.Height)
+}
+
+// Resizer is implemented by slides depending on the size of the viewport, like their projection.
+// HandleResize is called after InitGL and whenever the viewport changes size.
+type Resizer interface {
+	HandleResize(vp Viewport)
+}
+
+// Resize passes the viewport to the slide if it is a Resizer
+func Resize(s Slide, vp Viewport) {
+	if r, ok := s.(Resizer); ok {
+		r.HandleResize(vp)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// session is what is kept between runs: the slide the presentation was left on and the
+// state of the Stateful slides, by type name
+type session struct {
+	Slide  string                     `json:"slide"`
+	Slides map[string]json.RawMessage `json:"slides"`
+
+	file string
+	// running are the slides whose state was loaded, only those are saved. A slide failing
+	// in InitGL would save the defaults over its state.
+	running map[sections.Slide]bool
+}
+
+// loadSession reads the state file, a missing file or an empty name give an empty session.
+// A file that cant be read gives an empty session too, with the error.
+func loadSession(file string) (*session, error) {
+	empty := func() *session {
+		return &session{file: file, Slides: make(map[string]json.RawMessage), running: make(map[sections.Slide]bool)}
+	}
+	s := empty()
+	if file == "" {
+		return s, nil
+	}
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return empty(), err
+	}
+	if s.Slides == nil {
+		s.Slides = make(map[string]json.RawMessage)
+	}
+	return s, nil
+}
+
+// startSlide returns the index of the slide the presentation was left on, or def when it
+// isnt there anymore
+func (ss *session) startSlide(slides []sections.Slide, def int) int {
+	if ss.Slide == "" {
+		return def
+	}
+	if i, err := findSlide(slides, ss.Slide); err == nil {
+		return i
+	}
+	return def
+}
+
+// restore gives the slide its saved state, it is called once InitGL succeeded
+func (ss *session) restore(s sections.Slide) {
+	st, ok := s.(sections.Stateful)
+	if !ok {
+		return
+	}
+	ss.running[s] = true
+	data, ok := ss.Slides[slideType(s)]
+	if !ok {
+		return
+	}
+	if err := st.LoadState(data); err != nil {
+		log.Printf("cant restore the state of %s: %v", s.GetHeader(), err)
+	}
+}
+
+// keep saves the state of a slide about to be closed
+func (ss *session) keep(s sections.Slide) {
+	if !ss.running[s] {
+		return
+	}
+	delete(ss.running, s)
+	data, err := s.(sections.Stateful).SaveState()
+	if err != nil {
+		log.Printf("cant save the state of %s: %v", s.GetHeader(), err)
+		return
+	}
+	ss.Slides[slideType(s)] = data
+}
+
+// reset forgets the saved state of the slide and puts it back to its defaults
+func (ss *session) reset(s sections.Slide) {
+	delete(ss.Slides, slideType(s))
+	if st, ok := s.(sections.Stateful); ok {
+		st.ResetState()
+	}
+}
+
+// save writes the session to the state file, with the current slide
+func (ss *session) save() error {
+	if ss.file == "" {
+		return nil
+	}
+	ss.Slide = slides[slideIndex].GetHeader()
+	b, err := json.MarshalIndent(ss, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ss.file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(ss.file, b, 0644)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// statefulSlide keeps a value between runs, like a tweak of the textures exercise
+type statefulSlide struct {
+	sections.BaseSketch
+	value int
+	// failLoad makes LoadState fail
+	failLoad bool
+}
+
+func (s *statefulSlide) SaveState() (json.RawMessage, error) {
+	return json.Marshal(s.value)
+}
+
+func (s *statefulSlide) LoadState(data json.RawMessage) error {
+	if s.failLoad {
+		return errors.New("bad state")
+	}
+	return json.Unmarshal(data, &s.value)
+}
+
+func (s *statefulSlide) ResetState() {
+	s.value = 0
+}
+
+func newStatefulSlide(name string) *statefulSlide {
+	s := &statefulSlide{}
+	s.SetName(name)
+	return s
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	defer func(s []sections.Slide, i int) { slides, slideIndex = s, i }(slides, slideIndex)
+	file := filepath.Join(t.TempDir(), "config", "state.json")
+
+	ss, err := loadSession(file)
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	st := newStatefulSlide("Tweak")
+	other := &goldenSlide{}
+	other.SetName("Other")
+	slides, slideIndex = []sections.Slide{other, st}, 1
+
+	ss.restore(st)
+	ss.restore(other)
+	st.value = 7
+	ss.keep(st)
+	ss.keep(other)
+	if err := ss.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err = loadSession(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss.Slide != "Tweak" {
+		t.Errorf("saved slide %q, want Tweak", ss.Slide)
+	}
+	if got := ss.startSlide(slides, 0); got != 1 {
+		t.Errorf("start slide %d, want 1", got)
+	}
+	if len(ss.Slides) != 1 {
+		t.Errorf("saved %d states, want only the stateful slide", len(ss.Slides))
+	}
+	restored := newStatefulSlide("Tweak")
+	ss.restore(restored)
+	if restored.value != 7 {
+		t.Errorf("restored %d, want 7", restored.value)
+	}
+}
+
+func TestSessionKeepsOnlyRunningSlides(t *testing.T) {
+	ss, _ := loadSession("")
+	st := newStatefulSlide("Tweak")
+	st.value = 3
+	// a slide that failed before being restored saves nothing over its state
+	ss.keep(st)
+	if len(ss.Slides) != 0 {
+		t.Errorf("saved %v", ss.Slides)
+	}
+	ss.restore(st)
+	ss.keep(st)
+	ss.keep(st)
+	if string(ss.Slides["statefulSlide"]) != "3" {
+		t.Errorf("saved %s, want 3", ss.Slides["statefulSlide"])
+	}
+	if ss.running[st] {
+		t.Error("the closed slide is still running")
+	}
+}
+
+func TestSessionReset(t *testing.T) {
+	ss, _ := loadSession("")
+	ss.Slides["statefulSlide"] = json.RawMessage("5")
+	st := newStatefulSlide("Tweak")
+	ss.restore(st)
+	ss.reset(st)
+	if st.value != 0 {
+		t.Errorf("value %d after reset", st.value)
+	}
+	if _, ok := ss.Slides["statefulSlide"]; ok {
+		t.Error("the saved state wasnt forgotten")
+	}
+}
+
+func TestLoadSessionErrors(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		f := filepath.Join(dir, name)
+		if err := os.WriteFile(f, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		err     bool
+		slide   string
+		entries int
+	}{
+		{"no file", "", false, "", 0},
+		{"missing", filepath.Join(dir, "missing.json"), false, "", 0},
+		{"a directory", dir, true, "", 0},
+		{"corrupt", write("corrupt.json", `{"slide": "Tweak", "slides": {`), true, "", 0},
+		{"wrong types", write("types.json", `{"slide": "Tweak", "slides": 3}`), true, "", 0},
+		{"no slides", write("empty.json", `{"slide": "Tweak"}`), false, "Tweak", 0},
+		{"removed slide", write("removed.json", `{"slide": "Gone", "slides": {"goneSlide": 1, "statefulSlide": 2}}`), false, "Gone", 2},
+	}
+	for _, tt := range tests {
+		ss, err := loadSession(tt.file)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error %v, want an error %v", tt.name, err, tt.err)
+		}
+		if ss == nil || ss.Slides == nil || ss.running == nil {
+			t.Errorf("%s: unusable session %+v", tt.name, ss)
+			continue
+		}
+		if ss.Slide != tt.slide || len(ss.Slides) != tt.entries {
+			t.Errorf("%s: slide %q with %d states, want %q with %d", tt.name, ss.Slide, len(ss.Slides), tt.slide, tt.entries)
+		}
+		// the session works whatever happened
+		st := newStatefulSlide("Tweak")
+		ss.restore(st)
+		ss.keep(st)
+	}
+}
+
+func TestSessionRemovedSlide(t *testing.T) {
+	defer func(s []sections.Slide, i int) { slides, slideIndex = s, i }(slides, slideIndex)
+	file := filepath.Join(t.TempDir(), "state.json")
+	data := `{"slide": "Gone", "slides": {"goneSlide": {"x": 1}, "statefulSlide": 2}}`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ss, err := loadSession(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := newStatefulSlide("Tweak")
+	slides, slideIndex = []sections.Slide{newStatefulSlide("First"), st}, 1
+
+	// the presentation starts on the default slide and the others get their state
+	if got := ss.startSlide(slides, 0); got != 0 {
+		t.Errorf("start slide %d, want the default 0", got)
+	}
+	ss.restore(st)
+	if st.value != 2 {
+		t.Errorf("restored %d, want 2", st.value)
+	}
+
+	// the state of the removed slide is kept in case it comes back
+	ss.keep(st)
+	if err := ss.save(); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := loadSession(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]json.RawMessage{"goneSlide": json.RawMessage(`{"x":1}`), "statefulSlide": json.RawMessage("2")}
+	for k, v := range saved.Slides {
+		compact := new(bytes.Buffer)
+		if err := json.Compact(compact, v); err != nil {
+			t.Fatal(err)
+		}
+		saved.Slides[k] = compact.Bytes()
+	}
+	if !reflect.DeepEqual(saved.Slides, want) {
+		t.Errorf("saved %s, want %s", saved.Slides, want)
+	}
+}
+
+func TestSessionStateLoadFails(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	ss, _ := loadSession("")
+	ss.Slides["statefulSlide"] = json.RawMessage("5")
+	st := newStatefulSlide("Tweak")
+	st.failLoad = true
+	ss.restore(st)
+	if st.value != 0 || !ss.running[st] {
+		t.Errorf("value %d, running %v", st.value, ss.running[st])
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"log"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/raedatoui/glutils"
+	"github.com/raedatoui/learn-opengl-golang/sections"
+)
+
+// transition plays an effect between the outgoing and the incoming slides. Both are running
+// and drawn into their own framebuffer, the outgoing one is closed when it ends.
+type transition struct {
+	effect    sections.Transition
+	from, to  sections.Slide
+	direction float32
+	duration  float64
+	elapsed   float64
+	lastTime  float64
+	r         *transitionRenderer
+}
+
+// transitionRenderer holds the program and the framebuffers of the transitions. It is created
+// with the first transition and kept for the next ones, the framebuffers are reallocated when
+// the window changed size in between.
+type transitionRenderer struct {
+	program  uint32
+	vao      uint32
+	uniforms map[string]int32
+	fbFrom   *framebuffer
+	fbTo     *framebuffer
+}
+
+var transitionVertexShader = `
+#version 330 core
+out vec2 uv;
+void main() {
+	// a quad covering the window as a triangle strip, without a vertex buffer
+	uv = vec2(gl_VertexID & 1, gl_VertexID >> 1);
+	gl_Position = vec4(uv * 2.0 - 1.0, 0.0, 1.0);
+}` + "\x00"
+
+var transitionFragShader = `
+#version 330 core
+in vec2 uv;
+uniform sampler2D from;
+uniform sampler2D to;
+uniform float progress;
+uniform float direction;
+uniform int effect;
+out vec4 color;
+void main() {
+	float t = smoothstep(0.0, 1.0, progress);
+	if (effect == 1) {
+		// slide in, the incoming slide pushes the outgoing one out
+		vec2 p = uv + vec2(direction * t, 0.0);
+		if (p.x > 1.0 || p.x < 0.0) {
+			color = texture(to, p - vec2(direction, 0.0));
+		} else {
+			color = texture(from, p);
+		}
+	} else if (effect == 2) {
+		// wipe
+		float x = direction > 0.0 ? uv.x : 1.0 - uv.x;
+		color = x > 1.0 - t ? texture(to, uv) : texture(from, uv);
+	} else {
+		color = mix(texture(from, uv), texture(to, uv), t);
+	}
+}` + "\x00"
+
+// startTransition plays the effect from the outgoing slide to the current one, direction
+// is 1 when going forward and -1 when going back
+func startTransition(effect sections.Transition, from, to sections.Slide, direction float32, duration float64) (*transition, error) {
+	if transitions == nil {
+		r, err := newTransitionRenderer()
+		if err != nil {
+			return nil, err
+		}
+		transitions = r
+	}
+	if err := transitions.fit(viewport.Width, viewport.Height); err != nil {
+		return nil, err
+	}
+	return &transition{
+		effect:    effect,
+		from:      from,
+		to:        to,
+		direction: direction,
+		duration:  duration,
+		lastTime:  glfw.GetTime(),
+		r:         transitions,
+	}, nil
+}
+
+func newTransitionRenderer() (*transitionRenderer, error) {
+	program, err := glutils.BasicProgram(transitionVertexShader, transitionFragShader)
+	if err != nil {
+		return nil, err
+	}
+	r := &transitionRenderer{program: program, uniforms: make(map[string]int32)}
+	for _, u := range []string{"from", "to", "progress", "direction", "effect"} {
+		r.uniforms[u] = gl.GetUniformLocation(r.program, gl.Str(u+"\x00"))
+	}
+	gl.GenVertexArrays(1, &r.vao)
+	return r, nil
+}
+
+// fit allocates the framebuffers at the size of the window, unless they already are
+func (r *transitionRenderer) fit(width, height int) error {
+	if r.fbFrom != nil && r.fbFrom.width == int32(width) && r.fbFrom.height == int32(height) {
+		return nil
+	}
+	r.deleteFramebuffers()
+	var err error
+	if r.fbFrom, err = newFramebuffer(width, height); err != nil {
+		return err
+	}
+	if r.fbTo, err = newFramebuffer(width, height); err != nil {
+		r.deleteFramebuffers()
+		return err
+	}
+	return nil
+}
+
+func (r *transitionRenderer) deleteFramebuffers() {
+	if r.fbFrom != nil {
+		r.fbFrom.delete()
+		r.fbFrom = nil
+	}
+	if r.fbTo != nil {
+		r.fbTo.delete()
+		r.fbTo = nil
+	}
+}
+
+func (r *transitionRenderer) delete() {
+	r.deleteFramebuffers()
+	gl.DeleteVertexArrays(1, &r.vao)
+	gl.DeleteProgram(r.program)
+}
+
+// draw renders both slides and blends them, it returns false once the transition is over
+func (t *transition) draw() bool {
+	// with a fixed clock step the transition takes the same number of frames every time,
+	// so it comes out the same in recordings
+	now := glfw.GetTime()
+	if clock.FixedStep > 0 {
+		t.elapsed += clock.FixedStep
+	} else {
+		t.elapsed += now - t.lastTime
+	}
+	t.lastTime = now
+	if t.elapsed >= t.duration {
+		return false
+	}
+
+	r := t.r
+	if err := t.render(t.from, r.fbFrom); err != nil {
+		// a broken slide isnt worth keeping in the cache
+		log.Printf("slide %s failed: %v", t.from.GetHeader(), err)
+		closeSlide(t.from)
+		t.from = nil
+		return false
+	}
+	if err := t.render(t.to, r.fbTo); err != nil {
+		// the error slide takes over, it has nothing to transition to
+		showError(t.to, err)
+		return false
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(0, 0, int32(viewport.Width), int32(viewport.Height))
+	sections.OverlayRenderState().Apply()
+	gl.UseProgram(r.program)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, r.fbFrom.texture)
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, r.fbTo.texture)
+	gl.Uniform1i(r.uniforms["from"], 0)
+	gl.Uniform1i(r.uniforms["to"], 1)
+	gl.Uniform1f(r.uniforms["progress"], float32(t.elapsed/t.duration))
+	gl.Uniform1f(r.uniforms["direction"], t.direction)
+	gl.Uniform1i(r.uniforms["effect"], int32(t.effect))
+	gl.BindVertexArray(r.vao)
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	gl.BindVertexArray(0)
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	sections.DefaultRenderState().Apply()
+	return true
+}
+
+// render runs a frame of the slide into the framebuffer, with its header
+func (t *transition) render(s sections.Slide, fb *framebuffer) error {
+	fb.bind()
+	renderState(s).Apply()
+	err := protect(func() error {
+		defer endPhase()
+		beginPhase(s, "Update")
+		s.Update()
+		beginPhase(s, "Draw")
+		s.Draw(viewport)
+		return nil
+	})
+	sections.DefaultRenderState().Apply()
+	if err == nil {
+		drawText(s)
+	}
+	return err
+}
+
+// finish suspends the outgoing slide, unless it failed and was closed, the framebuffers are
+// kept for the next transition
+func (t *transition) finish() {
+	if t.from != nil {
+		suspended.suspend(t.from)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(0, 0, int32(viewport.Width), int32(viewport.Height))
+}
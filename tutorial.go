@@ -6,27 +6,65 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"runtime/debug"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/raedatoui/glfont"
 	"github.com/raedatoui/glutils"
 	"github.com/raedatoui/learn-opengl-golang/sections"
-	"github.com/raedatoui/learn-opengl-golang/sections/getstarted"
-	"github.com/raedatoui/learn-opengl-golang/sections/lighting"
-	"github.com/raedatoui/learn-opengl-golang/sections/modelloading"
+	_ "github.com/raedatoui/learn-opengl-golang/sections/getstarted"
+	_ "github.com/raedatoui/learn-opengl-golang/sections/lighting"
+	_ "github.com/raedatoui/learn-opengl-golang/sections/modelloading"
 )
 
 var (
 	currentSlide sections.Slide
 	slides       []sections.Slide
 	covers       map[int]sections.Slide
+	errorSlide   *sections.ErrorSlide
 	slideIndex   = 0
 	window       *glfw.Window
-	font         *glfont.Font
-	keys         map[glfw.Key]bool
-	wireframe    int32
+	viewport     sections.Viewport
+	// glReport collects the GL errors of the slides in debug mode
+	glReport  *sections.GLReport
+	font      *glfont.Font
+	clock     *sections.Clock
+	keys      map[glfw.Key]bool
+	bindings  map[glfw.Key]action
+	showHelp  bool
+	reloadErr error
+	// perfHUD is the performance overlay, nil when hidden
+	perfHUD *hud
+	stats   frameStats
+	// screenshotPending and recordPending are set by the actions and handled after the frame is drawn
+	screenshotPending bool
+	recordPending     bool
+	rec               *recorder
+	// inputRec and player record and replay the input events, frameCount stamps them
+	inputRec   *inputRecorder
+	player     *inputPlayer
+	frameCount int
+	// pal is the command palette, nil when closed
+	pal *palette
+	// ov is the overview grid, nil when the slides are showing
+	ov *overview
+	// activeTransition is playing between two slides, transitions draws them and slideTransitions
+	// are the section defaults
+	activeTransition *transition
+	transitions      *transitionRenderer
+	slideTransitions map[sections.Slide]sections.Transition
+	transitionMode   string
+	transitionTime   float64
+	// suspended keeps the slides left recently running, so going back to them is instant
+	suspended *slideCache
+	// sess keeps the slide and the state of the slides between runs
+	sess *session
+	// polygonModes are the modes picked with the wireframe action, they override the slides state
+	polygonModes = make(map[sections.Slide]uint32)
 )
 
 func init() {
@@ -35,67 +73,33 @@ func init() {
 	runtime.LockOSThread()
 }
 
-func init() {
-	dir, err := glutils.ImportPathToDir("github.com/raedatoui/learn-opengl-golang")
-	if err != nil {
-		log.Fatalln("Unable to find Go package in your GOPATH, it's needed to load assets:", err)
-	}
-	if err := os.Chdir(dir); err != nil {
-		log.Panicln("os.Chdir:", err)
-	}
-}
-
 func keyCallBack(w *glfw.Window, k glfw.Key, s int, a glfw.Action, mk glfw.ModifierKey) {
-	if a == glfw.Press {
-		if k == glfw.KeyEscape {
-			window.SetShouldClose(true)
-		}
-		if k == glfw.KeySpace {
-			gl.GetIntegerv(gl.POLYGON_MODE, &wireframe)
-			switch wireframe {
-			case gl.FILL:
-				gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
-			case gl.LINE:
-				gl.PolygonMode(gl.FRONT_AND_BACK, gl.POINT)
-				gl.PointSize(20.0)
-			case gl.POINT:
-				gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
-			}
-		}
+	handleInput(inputEvent{Kind: keyEvent, Key: k, Scancode: s, Action: a, Mods: mk})
+}
 
-		if k >= glfw.Key0 && k <= glfw.Key9 {
-			c := int(k) - 48
-			if c < len(covers) {
-				slideIndex = sections.SlidePosition(slides, covers[c])
-				currentSlide.Close()
-				currentSlide = slides[slideIndex]
-				if err := currentSlide.InitGL(); err != nil {
-					log.Fatalf("slide failed %v: ", err)
-				}
-				return
-			}
+// handleKey runs the action bound to the key or passes it to the slide
+func handleKey(k glfw.Key, s int, a glfw.Action, mk glfw.ModifierKey) {
+	if pal != nil {
+		if a != glfw.Release {
+			pal.handleKey(k)
 		}
-
-		newIndex := slideIndex
-		if s == 124 {
-			newIndex = slideIndex + 1
-			if newIndex > len(slides)-1 {
-				newIndex = len(slides) - 1
-			}
+		return
+	}
+	if ov != nil {
+		if a == glfw.Release {
+			return
 		}
-		if s == 123 {
-			newIndex = slideIndex - 1
-			if newIndex < 0 {
-				newIndex = 0
-			}
+		// the overview takes the arrows, enter and escape whatever they are bound to
+		if act := bindings[k]; act.name == "overview" {
+			doAction(act)
+		} else {
+			ov.handleKey(k)
 		}
-		if newIndex != slideIndex {
-			slideIndex = newIndex
-			currentSlide.Close()
-			currentSlide = slides[newIndex]
-			if err := currentSlide.InitGL(); err != nil {
-				log.Fatalf("slide failed %v: ", err)
-			}
+		return
+	}
+	if a == glfw.Press {
+		if act, ok := bindings[k]; ok {
+			doAction(act)
 			return
 		}
 		keys[k] = true
@@ -110,40 +114,363 @@ func keyCallBack(w *glfw.Window, k glfw.Key, s int, a glfw.Action, mk glfw.Modif
 
 }
 
+// doAction runs an action bound to a key
+func doAction(a action) {
+	switch a.name {
+	case "next":
+		gotoSlide(slideIndex + 1)
+	case "previous":
+		gotoSlide(slideIndex - 1)
+	case "section":
+		if cover, ok := covers[a.section]; ok {
+			gotoSlide(sections.SlidePosition(slides, cover))
+		}
+	case "wireframe":
+		switch renderState(currentSlide).PolygonMode {
+		case gl.FILL:
+			polygonModes[currentSlide] = gl.LINE
+		case gl.LINE:
+			polygonModes[currentSlide] = gl.POINT
+		default:
+			polygonModes[currentSlide] = gl.FILL
+		}
+	case "quit":
+		window.SetShouldClose(true)
+	case "help":
+		showHelp = !showHelp
+	case "hud":
+		toggleHUD()
+	case "screenshot":
+		screenshotPending = true
+	case "record":
+		recordPending = true
+	case "overview":
+		toggleOverview()
+	case "palette":
+		if pal == nil {
+			p, err := openPalette()
+			if err != nil {
+				log.Printf("cant open the palette: %v", err)
+				return
+			}
+			pal = p
+		}
+	case "reset":
+		sess.reset(currentSlide)
+	case "pause":
+		clock.TogglePause()
+	case "step":
+		clock.Step()
+	case "slower":
+		if clock.Scale > 1.0/16 {
+			clock.Scale /= 2
+		}
+	case "faster":
+		if clock.Scale < 4 {
+			clock.Scale *= 2
+		}
+	case "camera-mode", "camera-cursor", "camera-fit":
+		c, ok := currentSlide.(sections.CameraControls)
+		if !ok {
+			return
+		}
+		switch a.name {
+		case "camera-mode":
+			c.CycleMode()
+		case "camera-cursor":
+			c.ToggleCapture()
+		default:
+			c.Fit()
+		}
+	}
+}
+
+// toggleHUD creates the performance overlay or frees it
+func toggleHUD() {
+	if perfHUD != nil {
+		perfHUD.delete()
+		perfHUD = nil
+		return
+	}
+	h, err := newHUD()
+	if err != nil {
+		log.Printf("cant create the HUD: %v", err)
+		return
+	}
+	perfHUD = h
+}
+
+// toggleOverview opens the overview, or closes it on the selected slide
+func toggleOverview() {
+	endTransition()
+	if ov != nil {
+		closeOverview(ov.selected)
+		return
+	}
+	o, err := openOverview()
+	if err != nil {
+		log.Printf("cant open the overview: %v", err)
+		return
+	}
+	ov = o
+}
+
+// closeOverview goes to slide i, it is the current slide once the overview is closed
+func closeOverview(i int) {
+	ov.close(i)
+	ov = nil
+}
+
+func closePalette() {
+	pal.delete()
+	pal = nil
+}
+
+// gotoSlide closes the current slide and initializes the one at index i
+func gotoSlide(i int) {
+	if i < 0 || i >= len(slides) || i == slideIndex {
+		return
+	}
+	endTransition()
+	direction := float32(1)
+	if i < slideIndex {
+		direction = -1
+	}
+	slideIndex = i
+	reloadErr = nil
+	from := currentSlide
+	initSlide(slides[i])
+	if from == currentSlide {
+		// the error slide replaced the error slide
+		return
+	}
+
+	effect := transitionFor(currentSlide)
+	if effect == sections.Cut || currentSlide == sections.Slide(errorSlide) {
+		suspended.suspend(from)
+		return
+	}
+	t, err := startTransition(effect, from, currentSlide, direction, transitionTime)
+	if err != nil {
+		log.Printf("cant start the transition: %v", err)
+		suspended.suspend(from)
+		return
+	}
+	activeTransition = t
+}
+
+// transitionFor returns the effect picked with -transition, or the default of the section of the slide
+func transitionFor(s sections.Slide) sections.Transition {
+	if transitionMode != "section" {
+		t, _ := sections.ParseTransition(transitionMode)
+		return t
+	}
+	return slideTransitions[s]
+}
+
+// endTransition closes the outgoing slide of the transition that is playing
+func endTransition() {
+	if activeTransition != nil {
+		activeTransition.finish()
+		activeTransition = nil
+	}
+}
+
+// initSlide makes s the current slide, resuming it if it is suspended. The error slide is
+// shown instead if InitGL fails.
+func initSlide(s sections.Slide) {
+	currentSlide = s
+	resumed, err := suspended.resume(s)
+	if !resumed {
+		err = protect(func() error {
+			beginPhase(s, "InitGL")
+			defer endPhase()
+			if err := s.InitGL(); err != nil {
+				return err
+			}
+			sections.Resize(s, viewport)
+			return nil
+		})
+	}
+	if err != nil {
+		showError(s, err)
+		return
+	}
+	if !resumed {
+		sess.restore(s)
+	}
+}
+
+// beginPhase attributes the GL errors to a phase of the slide until endPhase, in debug mode
+func beginPhase(s sections.Slide, phase string) {
+	if glReport != nil {
+		glReport.Begin(s.GetHeader(), phase)
+	}
+}
+
+func endPhase() {
+	if glReport != nil {
+		glReport.End()
+	}
+}
+
+// closeSlide closes the slide, frees whatever it left behind, even when Close panicked,
+// and restores the default render state
+func closeSlide(s sections.Slide) {
+	sess.keep(s)
+	beginPhase(s, "Close")
+	if err := protect(func() error {
+		s.Close()
+		return nil
+	}); err != nil {
+		log.Printf("slide %s failed closing: %v", s.GetHeader(), err)
+	}
+	s.ReleaseResources()
+	endPhase()
+	sections.DefaultRenderState().Apply()
+}
+
+// showError closes a failed slide and displays the error slide in its place.
+// slideIndex is left untouched so the navigation keeps working.
+func showError(s sections.Slide, err error) {
+	log.Printf("slide %s failed: %v", s.GetHeader(), err)
+	closeSlide(s)
+	errorSlide.SetError(s.GetHeader(), err)
+	currentSlide = errorSlide
+}
+
+// protect runs f and turns a panic into an error with the stack trace
+func protect(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return f()
+}
+
 func mouseCallback(w *glfw.Window, xpos float64, ypos float64) {
+	handleInput(inputEvent{Kind: mouseEvent, X: xpos, Y: ypos})
+}
+
+func handleMouse(xpos float64, ypos float64) {
+	if ov != nil {
+		ov.handleMouse(xpos, ypos)
+		return
+	}
 	if currentSlide != nil {
 		currentSlide.HandleMousePosition(xpos, ypos)
 	}
 }
 
 func scrollCallback(w *glfw.Window, xoff float64, yoff float64) {
-	if currentSlide != nil {
+	handleInput(inputEvent{Kind: scrollEvent, X: xoff, Y: yoff})
+}
+
+func handleScroll(xoff float64, yoff float64) {
+	if currentSlide != nil && ov == nil {
 		currentSlide.HandleScroll(xoff, yoff)
 	}
 }
 
+func charCallback(w *glfw.Window, char rune) {
+	handleInput(inputEvent{Kind: charEvent, Char: char})
+}
+
+// handleChar types in the palette, the slides only get the keys
+func handleChar(char rune) {
+	if pal != nil {
+		pal.handleChar(char)
+	}
+}
+
+func mouseButtonCallback(w *glfw.Window, b glfw.MouseButton, a glfw.Action, mk glfw.ModifierKey) {
+	handleInput(inputEvent{Kind: mouseButtonEvent, Button: b, Action: a, Mods: mk})
+}
+
+// handleMouseButton picks a slide in the overview, the slides dont use the buttons
+func handleMouseButton(b glfw.MouseButton, a glfw.Action, mk glfw.ModifierKey) {
+	if ov != nil && b == glfw.MouseButtonLeft && a == glfw.Press {
+		ov.click()
+	}
+}
+
+// resizeCallback is called when the framebuffer changes size
 func resizeCallback(w *glfw.Window, width int, height int) {
-	sections.WIDTH = float64(width)
-	sections.HEIGHT = float64(height)
-	sections.Ratio = float32(sections.WIDTH / sections.HEIGHT)
-	font.Resize(sections.WIDTH, sections.HEIGHT)
+	ww, wh := w.GetSize()
+	handleInput(inputEvent{Kind: resizeEvent, Width: width, Height: height, WindowWidth: ww, WindowHeight: wh})
+}
+
+// handleResize lays the slides out for a framebuffer of width x height in a ww x wh window.
+// The text is laid out in window coordinates so it keeps its size on HiDPI screens. A
+// replayed resize resizes the window too.
+func handleResize(width, height, ww, wh int) {
+	if w, h := window.GetSize(); w != ww || h != wh {
+		window.SetSize(ww, wh)
+	}
+	// the transition framebuffers have the old size
+	endTransition()
+	viewport = sections.NewViewport(width, height, ww, wh)
+	font.Resize(float64(ww), float64(wh))
 	gl.Viewport(0, 0, int32(width), int32(height))
+	if ov != nil {
+		ov.resize()
+		return
+	}
+	if currentSlide != nil {
+		if err := protect(func() error {
+			sections.Resize(currentSlide, viewport)
+			return nil
+		}); err != nil {
+			showError(currentSlide, err)
+		}
+	}
 }
 
 func fileDropCallback(w *glfw.Window, names []string) {
+	handleInput(inputEvent{Kind: dropEvent, Files: names})
+}
+
+func handleDrop(names []string) {
 	if currentSlide != nil {
 		currentSlide.HandleFiles(names)
 	}
 }
 
-func setup() (*glfw.Window, error) {
+func windowHints() {
 	glfw.WindowHint(glfw.Resizable, glfw.True)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, gl.TRUE)
+}
+
+func setup(cfg *config) (*glfw.Window, error) {
+	windowHints()
+	if cfg.debug {
+		glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
+	}
+	if cfg.msaa > 0 {
+		glfw.WindowHint(glfw.Samples, cfg.msaa)
+	}
+
+	var monitor *glfw.Monitor
+	width, height := cfg.width, cfg.height
+	if cfg.fullscreen {
+		monitors := glfw.GetMonitors()
+		if cfg.monitor < 0 || cfg.monitor >= len(monitors) {
+			return nil, fmt.Errorf("monitor %d not found, %d connected", cfg.monitor, len(monitors))
+		}
+		monitor = monitors[cfg.monitor]
+		mode := monitor.GetVideoMode()
+		glfw.WindowHint(glfw.RedBits, mode.RedBits)
+		glfw.WindowHint(glfw.GreenBits, mode.GreenBits)
+		glfw.WindowHint(glfw.BlueBits, mode.BlueBits)
+		glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+		width, height = mode.Width, mode.Height
+	}
 
-	window, err := glfw.CreateWindow(int(sections.WIDTH), int(sections.HEIGHT), "learnopengl.com in Golang", nil, nil)
+	window, err := glfw.CreateWindow(width, height, "learnopengl.com in Golang", monitor, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +480,21 @@ func setup() (*glfw.Window, error) {
 	if err := gl.Init(); err != nil {
 		return nil, err
 	}
+	if cfg.debug {
+		glReport = sections.NewGLReport()
+		if !glReport.InstallDebugCallback() {
+			log.Println("KHR_debug isnt supported, only glGetError is checked")
+		}
+	}
+
+	if cfg.vsync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+	if cfg.msaa > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+	}
 
 	// Resize Callback
 	window.SetFramebufferSizeCallback(resizeCallback)
@@ -164,63 +506,207 @@ func setup() (*glfw.Window, error) {
 
 	// File Drag n Drop
 	window.SetDropCallback(fileDropCallback)
+	window.SetMouseButtonCallback(mouseButtonCallback)
+	window.SetCharCallback(charCallback)
 	version := gl.GoStr(gl.GetString(gl.VERSION))
 	glsl := gl.GoStr(gl.GetString(gl.SHADING_LANGUAGE_VERSION))
 	fmt.Println("OpenGL version", version, glsl)
 
-	width, height := window.GetFramebufferSize()
-	gl.Viewport(0, 0, int32(width), int32(height))
+	fbWidth, fbHeight := window.GetFramebufferSize()
+	winWidth, winHeight := window.GetSize()
+	viewport = sections.NewViewport(fbWidth, fbHeight, winWidth, winHeight)
+	gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
 
 	return window, nil
 }
 
+// setupSlides builds the slides from the registry. Every section starts with a cover
+// and the covers are indexed by section number for the num keys.
 func setupSlides() []sections.Slide {
-	// make a slice of pointers to sketch instances
-	return []sections.Slide{
-		new(sections.TitleSlide),
-		new(getstarted.HelloCube),
-		new(sections.TitleSlide),
-		new(getstarted.HelloWindow),
+	covers = make(map[int]sections.Slide)
+	slideTransitions = make(map[sections.Slide]sections.Transition)
+	// the covers list the sections and the slides, _assets/slides adds some prose to them
+	intro := ""
+	for _, sec := range sections.Sections {
+		intro += fmt.Sprintf("%d. %s\n", sec.Number, sec.Title)
+	}
+	cover := &sections.TitleSlide{File: "slides/intro.md", Markdown: intro}
+	cover.SetName("LearnOpenGL in Go")
+	covers[0] = cover
+
+	slides := []sections.Slide{cover}
+	for _, sec := range sections.Sections {
+		if sec.Number > 0 {
+			name := fmt.Sprintf("Section %d: %s", sec.Number, sec.Title)
+			outline := ""
+			for _, e := range sections.Entries(sec.Number) {
+				outline += fmt.Sprintf("- **%s** %s\n", e.Name, e.Description)
+			}
+			cover := &sections.TitleSlide{File: fmt.Sprintf("slides/section-%d.md", sec.Number), Markdown: outline}
+			cover.SetName(name)
+			covers[sec.Number] = cover
+			slideTransitions[cover] = sec.Transition
+			slides = append(slides, cover)
+		}
+		for _, e := range sections.Entries(sec.Number) {
+			slide := e.New()
+			slide.SetName(e.Name)
+			slideTransitions[slide] = sec.Transition
+			slides = append(slides, slide)
+		}
+	}
+	return slides
+}
 
-		new(getstarted.HelloTriangle),
-		new(getstarted.TriangleEx1),
-		new(getstarted.TriangleEx2),
+// renderState is the state the slide declares with the polygon mode picked by the wireframe action
+func renderState(s sections.Slide) sections.RenderState {
+	r := s.RenderState()
+	if mode, ok := polygonModes[s]; ok {
+		r.PolygonMode = mode
+		if mode == gl.POINT {
+			r.PointSize = 20.0
+		}
+	}
+	return r
+}
+
+// loadFont loads the font used for the headers and the title slides
+func loadFont() (*glfont.Font, error) {
+	//load font (fontfile, font scale, window width, window height
+	f, err := glfont.LoadFont(sections.Asset(sections.FontFile), int32(sections.FontSize), float64(viewport.WindowWidth), float64(viewport.WindowHeight))
+	if err != nil {
+		return nil, err
+	}
+	c := glutils.White.To32()
+	f.SetColor(c.R, c.G, c.B, 1.0)
+	return f, nil
+}
 
-		new(getstarted.HelloShaders),
-		new(getstarted.ShaderEx1),
-		new(getstarted.ShaderEx2),
-		new(getstarted.ShaderEx3),
-		new(getstarted.ShaderEx4),
+// initSlides creates the slides and the error slide, and builds them with their colors and the clock
+func initSlides(f *glfont.Font, clock *sections.Clock, debug bool) error {
+	slides = setupSlides()
+	ctx := sections.Context{
+		Font:     f,
+		Assets:   sections.Asset,
+		Clock:    clock,
+		Viewport: viewport,
+		Logger:   log.New(os.Stderr, "", log.LstdFlags),
+		Debug:    debug,
+	}
+	l := len(slides)
+	for x, slide := range slides {
+		ctx.Color = glutils.StepColor(glutils.Magenta, glutils.Black, l, x)
+		ctx.Title = slide.GetName()
+		if err := slide.Init(ctx); err != nil {
+			return fmt.Errorf("%s: %v", slide.GetName(), err)
+		}
+	}
 
-		new(getstarted.HelloTextures),
-		new(getstarted.TexturesEx1),
-		new(getstarted.TexturesEx2),
-		new(getstarted.TexturesEx3),
-		new(getstarted.TexturesEx4),
+	errorSlide = new(sections.ErrorSlide)
+	ctx.Color = glutils.StepColor(glutils.Magenta, glutils.Black, 2, 1)
+	ctx.Title = ""
+	return errorSlide.Init(ctx)
+}
 
-		new(getstarted.HelloTransformations),
-		new(getstarted.TransformationEx1),
-		new(getstarted.TransformationEx2),
+// drawHelp lists the key bindings on top of the slide
+func drawHelp() {
+	font.SetColor(1.0, 1.0, 1.0, 1.0)
+	for i, l := range helpLines(bindings) {
+		font.Printf(float32(viewport.WindowWidth)/2, 100+22*float32(i), 0.3, l)
+	}
+}
 
-		new(getstarted.HelloCoordinates),
-		new(getstarted.HelloCamera),
+// updateCursor hides and grabs the cursor while the current slide asks for it,
+// like a camera slide after pressing C
+func updateCursor() {
+	mode := glfw.CursorNormal
+	if c, ok := currentSlide.(sections.CursorCapturer); ok && c.CursorCaptured() && ov == nil {
+		mode = glfw.CursorDisabled
+	}
+	if window.GetInputMode(glfw.CursorMode) != mode {
+		window.SetInputMode(glfw.CursorMode, mode)
+	}
+}
 
-		new(sections.TitleSlide),
-		new(lighting.LightingColors),
-		new(lighting.BasicSpecular),
-		new(lighting.Materials),
+// watchShaders recompiles the shaders of the current slide when their files change,
+// the error of a failed compilation stays on screen until the next successful one
+func watchShaders() {
+	changed, err := currentSlide.ReloadShaders()
+	if !changed {
+		return
+	}
+	reloadErr = err
+	if err != nil {
+		log.Printf("shader reload failed: %v", err)
+	}
+}
 
-		new(sections.TitleSlide),
-		new(modelloading.ModelLoading),
+// drawReloadError prints the last shader compilation error above the footer
+func drawReloadError() {
+	lines := strings.Split(reloadErr.Error(), "\n")
+	if len(lines) > 8 {
+		lines = lines[:8]
+	}
+	font.SetColor(1.0, 0.3, 0.3, 1.0)
+	for i, l := range lines {
+		font.Printf(30, float32(viewport.WindowHeight)-60-18*float32(len(lines)-1-i), 0.25, l)
+	}
+	font.SetColor(1.0, 1.0, 1.0, 1.0)
+}
 
-		new(sections.TitleSlide),
-		new(sections.TitleSlide),
-		new(sections.TitleSlide),
-		new(sections.TitleSlide),
+// drawText prints the slide header and the footer on top of the slide
+func drawText(s sections.Slide) {
+	if s.DrawText() {
+		font.Printf(30, 30, 0.5, s.GetHeader())
+		if s.GetSubHeader() != "" {
+			font.Printf(30, 50, 0.3, s.GetSubHeader())
+		}
 	}
+	font.Printf(30, float32(viewport.WindowHeight)-20, 0.2, s.GetColorHex())
+}
+
+// updateAndDraw runs a frame of the current slide and times it for the HUD
+func updateAndDraw() error {
+	return protect(func() error {
+		defer endPhase()
+		beginPhase(currentSlide, "Update")
+		start := time.Now()
+		currentSlide.Update()
+		stats.update = time.Since(start)
+
+		beginPhase(currentSlide, "Draw")
+		if perfHUD != nil {
+			perfHUD.gpu.begin()
+			// a panicking slide mustnt leave the queries running
+			defer perfHUD.gpu.end()
+		}
+		start = time.Now()
+		currentSlide.Draw(viewport)
+		stats.draw = time.Since(start)
+		return nil
+	})
 }
 
 func main() {
+	cfg := parseFlags()
+	if err := setupAssets(cfg.assets); err != nil {
+		log.Fatalln(err)
+	}
+
+	if cfg.bench > 0 {
+		if err := runBench(cfg.bench, cfg.width, cfg.height, cfg.benchOut); err != nil {
+			log.Fatalf("bench failed: %v", err)
+		}
+		return
+	}
+
+	if cfg.headless {
+		if err := renderHeadless(cfg.slide, cfg.width, cfg.height, cfg.time, cfg.out); err != nil {
+			log.Fatalf("headless render failed: %v", err)
+		}
+		return
+	}
+
 	// init GLFW
 	if err := glfw.Init(); err != nil {
 		log.Fatalln("failed to initialize glfw:", err)
@@ -228,105 +714,155 @@ func main() {
 	defer glfw.Terminate()
 
 	keys = make(map[glfw.Key]bool)
+	b, err := loadBindings(cfg.keys)
+	if err != nil {
+		log.Fatalf("cant load the key bindings: %v", err)
+	}
+	bindings = b
+	sections.SetCameraKeys(boundKey(b, "camera-mode"), boundKey(b, "camera-cursor"), boundKey(b, "camera-fit"))
 
 	// create window
-	w, err := setup()
+	w, err := setup(cfg)
 	if err != nil {
 		log.Fatalf("cant create window %v", err)
 	}
 	window = w
 
-	//load font (fontfile, font scale, window width, window height
-	f, err := glfont.LoadFont("_assets/fonts/huge_agb_v5.ttf", int32(52), sections.WIDTH, sections.HEIGHT)
+	f, err := loadFont()
 	if err != nil {
 		log.Fatalf("LoadFont: %v", err)
 	}
 	font = f
-	c := glutils.White.To32()
-	font.SetColor(c.R, c.G, c.B, 1.0)
 
-	slides = setupSlides()
-	l := len(slides)
-	covers = make(map[int]sections.Slide)
-	titles := []string{
-		"LearnOpenGL in Go\n \n" +
-			"0. Test installation using go-gl\n" +
-			"1. Getting Started\n" +
-			"2. Lighting\n" +
-			"3. Model Loading\n" +
-			"4. Advanced OpenGL\n" +
-			"5. Advanced Lighting\n" +
-			"6. PBR\n" +
-			"7. In Practice",
-		"Section 1: Getting Started",
-		"Section 2: Lighting",
-		"Section 3: Model Loading",
-		"Section 4: Advanced OpenGL",
-		"Section 5: Advanced Lighting",
-		"Section 6: PBR",
-		"Section 7: In Practice",
-	}
-	count := 0
-	titleSlideType := &sections.TitleSlide{}
-	for x, slide := range slides {
-		c := glutils.StepColor(glutils.Magenta, glutils.Black, l, x)
+	clock = sections.NewClock(glfw.GetTime)
+	clock.FixedStep = cfg.fixedStep
 
-		if glutils.IsType(slide, titleSlideType) {
-			covers[count] = slide
-			if err := slide.Init(f, c, titles[count]); err != nil {
-				log.Fatalf("Failed setting up sketch: %v", err)
-			}
-			count += 1
-		} else {
-			if err := slide.Init(f, c); err != nil {
-				log.Fatalf("Failed setting up sketch: %v", err)
-			}
-		}
+	if err := initSlides(f, clock, cfg.debug); err != nil {
+		log.Fatalf("Failed setting up sketch: %v", err)
 	}
 
-	currentSlide = slides[0]
-
-	if err := currentSlide.InitGL(); err != nil {
-		log.Fatalf("Failed initializing GL for slide: %v", err)
+	if sess, err = loadSession(cfg.state); err != nil {
+		log.Printf("cant load the session state: %v", err)
 	}
+	suspended = newSlideCache(cfg.cache, cfg.cacheMemory<<20)
+	slideIndex, err = cfg.startSlide(slides)
+	if err != nil {
+		log.Fatalf("Cant find the start slide: %v", err)
+	}
+	if cfg.resume {
+		slideIndex = sess.startSlide(slides, slideIndex)
+	}
+	initSlide(slides[slideIndex])
 
-	// TODO: do we always need to enabled the depth test?
-	gl.Enable(gl.DEPTH_TEST)
-	//gl.DepthFunc(gl.LESS)
-
-	// TODO: how to effectively blend shapes that use a frag shader with
-	// TODO: transparency
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	sections.DefaultRenderState().Apply()
 
 	var maxAttrib int32
 	gl.GetIntegerv(gl.MAX_VERTEX_ATTRIBS, &maxAttrib)
 
+	if cfg.recordInput != "" && cfg.replay != "" {
+		log.Fatalln("-record-input and -replay cant be used together")
+	}
+	if cfg.recordInput != "" {
+		header := inputHeader{Slide: slideIndex, Width: viewport.WindowWidth, Height: viewport.WindowHeight, FixedStep: cfg.fixedStep}
+		if inputRec, err = newInputRecorder(cfg.recordInput, header); err != nil {
+			log.Fatalf("cant record the input: %v", err)
+		}
+	}
+	if cfg.replay != "" {
+		if player, err = loadInputRecording(cfg.replay); err != nil {
+			log.Fatalf("cant load the input recording: %v", err)
+		}
+		startReplay(player, cfg.fixedStep)
+	}
+
+	transitionMode, transitionTime = cfg.transition, cfg.transitionTime
+	if _, err := sections.ParseTransition(cfg.transition); err != nil && cfg.transition != "section" {
+		log.Fatalln(err)
+	}
+
 	glutils.InitFPS()
 
+	lastWatch := time.Now()
+
 	// loop
 	for !window.ShouldClose() {
+		if cfg.watch && time.Since(lastWatch) > 500*time.Millisecond {
+			lastWatch = time.Now()
+			watchShaders()
+		}
 
 		// Update
-		currentSlide.Update()
-
-		//Render
-		currentSlide.Draw()
-		if currentSlide.DrawText() {
-			font.Printf(30, 30, 0.5, currentSlide.GetHeader())
-			if currentSlide.GetSubHeader() != "" {
-				font.Printf(30, 50, 0.3, currentSlide.GetSubHeader())
+		clock.Tick()
+		// Render
+		if activeTransition != nil && !activeTransition.draw() {
+			endTransition()
+		}
+		if ov != nil {
+			ov.draw()
+		} else if activeTransition == nil {
+			renderState(currentSlide).Apply()
+			sections.ResetDrawCalls()
+			if err := updateAndDraw(); err != nil {
+				showError(currentSlide, err)
+			}
+			stats.drawCalls = sections.ResetDrawCalls()
+			sections.DefaultRenderState().Apply()
+			drawText(currentSlide)
+			if reloadErr != nil {
+				drawReloadError()
 			}
 		}
+		if showHelp {
+			drawHelp()
+		}
+
+		if pal != nil {
+			pal.draw()
+		}
+		if perfHUD != nil {
+			perfHUD.frame(stats)
+			perfHUD.render(font, viewport)
+		}
 
-		font.Printf(30, float32(sections.HEIGHT)-20, 0.2, currentSlide.GetColorHex())
 		fps := "FPS: " + strconv.FormatFloat(glutils.CalcFPS(1.0), 'f', 2, 64)
-		font.Printf(float32(sections.WIDTH)-80, float32(sections.HEIGHT)-20, 0.25, fps)
+		font.Printf(float32(viewport.WindowWidth)-80, float32(viewport.WindowHeight)-20, 0.25, fps)
+		if state := clock.String(); state != "" {
+			font.Printf(float32(viewport.WindowWidth)-160, float32(viewport.WindowHeight)-20, 0.25, state)
+		}
 
+		capture(cfg)
 		window.SwapBuffers()
 		// Poll Events
 		glfw.PollEvents()
+		if player != nil && !player.play() {
+			log.Println("replay finished")
+			player = nil
+		}
+		updateCursor()
+		frameCount++
+	}
+	if rec != nil {
+		toggleRecording(cfg)
+	}
+	if inputRec != nil {
+		if err := inputRec.close(); err != nil {
+			log.Printf("cant save the input recording: %v", err)
+		}
+	}
+	if ov != nil {
+		closeOverview(slideIndex)
+	}
+	endTransition()
+	if transitions != nil {
+		transitions.delete()
+	}
+	closeSlide(currentSlide)
+	suspended.clear()
+	if err := sess.save(); err != nil {
+		log.Printf("cant save the session state: %v", err)
+	}
+	if glReport != nil {
+		log.Printf("GL errors:\n%s", glReport)
 	}
-	currentSlide.Close()
 
 }